@@ -16,6 +16,10 @@ type LoginWithEmailDTO struct {
 	Password string `json:"password" binding:"required"`
 }
 
+type UpdateTimezoneDTO struct {
+	Timezone string `json:"timezone" binding:"required"`
+}
+
 type RegisterDTO struct {
 	Name     string `json:"name" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
@@ -0,0 +1,5 @@
+package auth
+
+import "errors"
+
+var ErrInvalidTimezone = errors.New("invalid time zone")
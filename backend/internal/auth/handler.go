@@ -134,6 +134,32 @@ func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusOK, user)
 }
 
+func (h *AuthHandler) UpdateTimezone(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(sharedauth.UserContextKey).(uuid.UUID)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "UNAUTHORIZED", "Contexto de usuário inválido")
+		return
+	}
+
+	var dto UpdateTimezoneDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_REQUEST", "Dados inválidos")
+		return
+	}
+
+	user, err := h.service.SetTimezone(userID.String(), dto.Timezone)
+	if err == ErrInvalidTimezone {
+		response.Error(w, http.StatusBadRequest, "INVALID_TIMEZONE", "Fuso horário inválido")
+		return
+	}
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Erro interno no servidor")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, user)
+}
+
 func (h *AuthHandler) GetService() *AuthService {
 	return h.service
 }
@@ -14,6 +14,7 @@ type User struct {
 	PasswordHash *string   `json:"-"`
 	Name         string    `gorm:"not null" json:"name"`
 	Picture      string    `json:"picture,omitempty"`
+	Timezone     string    `gorm:"not null;default:UTC" json:"timezone"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
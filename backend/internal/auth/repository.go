@@ -1,9 +1,21 @@
 package auth
 
 import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// Locator resolves the time zone a user's calendar days are counted in.
+// Features that group or expand dates by day take it instead of keeping a
+// time zone setting of their own.
+type Locator interface {
+	Location(ctx context.Context, userID uuid.UUID) (*time.Location, error)
+}
+
 type AuthRepository struct {
 	db *gorm.DB
 }
@@ -38,3 +50,25 @@ func (r *AuthRepository) CreateUser(user *User) error {
 	return r.db.Create(user).Error
 }
 
+func (r *AuthRepository) UpdateTimezone(id string, timezone string) error {
+	return r.db.Model(&User{}).Where("id = ?", id).Update("timezone", timezone).Error
+}
+
+// Location returns the user's time zone, or UTC for a user that no longer
+// exists.
+func (r *AuthRepository) Location(ctx context.Context, userID uuid.UUID) (*time.Location, error) {
+	var user User
+	err := r.db.WithContext(ctx).Select("timezone").Where("id = ?", userID).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.UTC, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		return time.UTC, nil
+	}
+	return loc, nil
+}
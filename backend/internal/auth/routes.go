@@ -20,6 +20,7 @@ func RegisterRoutes(r chi.Router, service *AuthService, cfg *config.Config) {
 		r.Group(func(r chi.Router) {
 			r.Use(middlewares.Auth(service.jwtService))
 			r.Get("/me", h.Me)
+			r.Put("/me/timezone", h.UpdateTimezone)
 		})
 	})
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"time"
 
 	sharedauth "github.com/saulo-duarte/chronos/internal/shared/auth"
 	"golang.org/x/oauth2"
@@ -110,3 +111,18 @@ func (s *AuthService) VerifyGoogleCode(ctx context.Context, config *oauth2.Confi
 func (s *AuthService) GetMe(userID string) (*User, error) {
 	return s.repo.GetByID(userID)
 }
+
+// SetTimezone saves the time zone the user's dates are shown and grouped in.
+func (s *AuthService) SetTimezone(userID string, timezone string) (*User, error) {
+	if timezone == "" || timezone == "Local" {
+		return nil, ErrInvalidTimezone
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return nil, ErrInvalidTimezone
+	}
+
+	if err := s.repo.UpdateTimezone(userID, timezone); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(userID)
+}
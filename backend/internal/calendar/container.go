@@ -0,0 +1,27 @@
+package calendar
+
+import (
+	"github.com/saulo-duarte/chronos/internal/auth"
+	"github.com/saulo-duarte/chronos/internal/collections"
+	"github.com/saulo-duarte/chronos/internal/leetcode"
+	"github.com/saulo-duarte/chronos/internal/tasks"
+	"gorm.io/gorm"
+)
+
+type Container struct {
+	Repository Repository
+	Service    Service
+	Handler    *Handler
+}
+
+func NewContainer(db *gorm.DB, taskService tasks.Service, leetcodeService leetcode.Service, collectionService collections.Service, locator auth.Locator) *Container {
+	repo := NewRepository(db)
+	svc := NewService(repo, taskService, leetcodeService, collectionService, locator)
+	hdl := NewHandler(svc)
+
+	return &Container{
+		Repository: repo,
+		Service:    svc,
+		Handler:    hdl,
+	}
+}
@@ -0,0 +1,94 @@
+package calendar
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos/internal/leetcode"
+	"github.com/saulo-duarte/chronos/internal/tasks"
+)
+
+type EventDTO struct {
+	Type         EventType  `json:"type"`
+	SourceID     uuid.UUID  `json:"source_id"`
+	Title        string     `json:"title"`
+	Start        time.Time  `json:"start"`
+	End          *time.Time `json:"end,omitempty"`
+	Status       string     `json:"status,omitempty"`
+	Priority     string     `json:"priority,omitempty"`
+	Difficulty   string     `json:"difficulty,omitempty"`
+	Tags         []string   `json:"tags,omitempty"`
+	CollectionID *uuid.UUID `json:"collection_id,omitempty"`
+	IsRecurring  bool       `json:"is_recurring"`
+	IsDone       bool       `json:"is_done"`
+}
+
+type DayDTO struct {
+	Date   string     `json:"date"`
+	Events []EventDTO `json:"events"`
+}
+
+type CalendarResponseDTO struct {
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Timezone string    `json:"timezone"`
+	Days     []DayDTO  `json:"days"`
+}
+
+type FeedResponseDTO struct {
+	URL       string    `json:"url"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ImportItemDTO struct {
+	UID        string         `json:"uid,omitempty"`
+	Component  string         `json:"component"`
+	Title      string         `json:"title"`
+	StartTime  *time.Time     `json:"start_time,omitempty"`
+	EndTime    *time.Time     `json:"end_time,omitempty"`
+	Status     tasks.Status   `json:"status,omitempty"`
+	Priority   tasks.Priority `json:"priority,omitempty"`
+	Recurrence *string        `json:"recurrence_rule,omitempty"`
+	Action     ImportAction   `json:"action"`
+	Reason     string         `json:"reason,omitempty"`
+	Warning    string         `json:"warning,omitempty"`
+	TaskID     *uuid.UUID     `json:"task_id,omitempty"`
+}
+
+type ImportResultDTO struct {
+	DryRun     bool            `json:"dry_run"`
+	Created    int             `json:"created"`
+	Duplicates int             `json:"duplicates"`
+	Skipped    int             `json:"skipped"`
+	Items      []ImportItemDTO `json:"items"`
+}
+
+func fromOccurrence(o tasks.OccurrenceResponseDTO, loc *time.Location) EventDTO {
+	event := EventDTO{
+		Type:         EventTask,
+		SourceID:     o.TaskID,
+		Title:        o.Title,
+		Start:        o.StartTime.In(loc),
+		Status:       string(o.Status),
+		Priority:     string(o.Priority),
+		CollectionID: o.CollectionID,
+		IsRecurring:  o.IsRecurring,
+		IsDone:       o.FinishedAt != nil,
+	}
+	if o.EndTime != nil {
+		end := o.EndTime.In(loc)
+		event.End = &end
+	}
+	return event
+}
+
+func fromReview(p leetcode.ProblemResponseDTO, loc *time.Location) EventDTO {
+	return EventDTO{
+		Type:       EventReview,
+		SourceID:   p.ID,
+		Title:      p.Title,
+		Start:      p.NextReview.In(loc),
+		Difficulty: string(p.Difficulty),
+		Tags:       p.Tags,
+	}
+}
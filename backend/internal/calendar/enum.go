@@ -0,0 +1,8 @@
+package calendar
+
+type EventType string
+
+const (
+	EventTask   EventType = "TASK"
+	EventReview EventType = "REVIEW"
+)
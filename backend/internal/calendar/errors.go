@@ -0,0 +1,13 @@
+package calendar
+
+import "errors"
+
+var (
+	ErrInvalidRange       = errors.New("invalid calendar range")
+	ErrInvalidTimezone    = errors.New("invalid timezone")
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrFeedNotFound       = errors.New("calendar feed not found")
+	ErrInvalidICS         = errors.New("invalid iCalendar file")
+	ErrCollectionNotFound = errors.New("collection not found")
+	ErrAlreadyImported    = errors.New("an event of the file was imported at the same time, try again")
+)
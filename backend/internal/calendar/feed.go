@@ -0,0 +1,196 @@
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/saulo-duarte/chronos/internal/leetcode"
+	"github.com/saulo-duarte/chronos/internal/tasks"
+)
+
+const reviewDuration = 30 * time.Minute
+
+// timezoneHorizon is how many years past now the VTIMEZONE lists offset
+// changes. Occurrences after that keep the last listed offset.
+const timezoneHorizon = 10
+
+var icsPriorities = map[tasks.Priority]string{
+	tasks.High:   "1",
+	tasks.Medium: "5",
+	tasks.Low:    "9",
+}
+
+// renderFeed renders tasks with an end time as VEVENTs, open-ended tasks as
+// VTODOs and the next review of each LeetCode problem as a short VEVENT.
+// Recurring tasks are written on the wall clock of loc with its VTIMEZONE,
+// since clients expand BYDAY and month days in the zone of DTSTART and the
+// rules are meant in the user's time zone.
+func renderFeed(taskList []tasks.TaskResponseDTO, problems []leetcode.ProblemResponseDTO, loc *time.Location, now time.Time) string {
+	w := &icsWriter{}
+	w.raw("BEGIN", "VCALENDAR")
+	w.raw("VERSION", "2.0")
+	w.raw("PRODID", "-//Chronos//Chronos Calendar//PT")
+	w.raw("CALSCALE", "GREGORIAN")
+	w.raw("METHOD", "PUBLISH")
+	w.text("X-WR-CALNAME", "Chronos")
+
+	var zone *time.Location
+	if loc != nil && loc != time.UTC {
+		var first *time.Time
+		for i := range taskList {
+			if taskList[i].Recurrence != nil && (first == nil || taskList[i].StartTime.Before(*first)) {
+				first = &taskList[i].StartTime
+			}
+		}
+		if first != nil {
+			zone = loc
+			writeTimezone(w, zone, *first, now.AddDate(timezoneHorizon, 0, 0))
+		}
+	}
+
+	for _, t := range taskList {
+		if t.EndTime != nil {
+			writeTaskEvent(w, t, zone, now)
+		} else {
+			writeTaskTodo(w, t, zone, now)
+		}
+	}
+
+	for _, p := range problems {
+		writeReviewEvent(w, p, now)
+	}
+
+	w.raw("END", "VCALENDAR")
+	return w.String()
+}
+
+// writeTimezone writes the VTIMEZONE of loc with one observance per offset
+// in effect between from and to, taken from the Go time zone database.
+func writeTimezone(w *icsWriter, loc *time.Location, from, to time.Time) {
+	w.raw("BEGIN", "VTIMEZONE")
+	w.raw("TZID", loc.String())
+
+	t := from.In(loc)
+	onset, _ := t.ZoneBounds()
+	if onset.IsZero() {
+		onset = t
+	}
+	_, offset := t.Zone()
+	writeObservance(w, onset.In(loc), offset)
+
+	for {
+		_, end := t.ZoneBounds()
+		if end.IsZero() || !end.Before(to) {
+			break
+		}
+		t = end.In(loc)
+		writeObservance(w, t, offset)
+		_, offset = t.Zone()
+	}
+
+	w.raw("END", "VTIMEZONE")
+}
+
+// writeObservance writes the STANDARD or DAYLIGHT period starting at onset.
+// Its DTSTART is the local time of the change under the previous offset.
+func writeObservance(w *icsWriter, onset time.Time, offsetFrom int) {
+	name, offset := onset.Zone()
+	kind := "STANDARD"
+	if onset.IsDST() {
+		kind = "DAYLIGHT"
+	}
+
+	w.raw("BEGIN", kind)
+	w.raw("DTSTART", onset.In(time.FixedZone("", offsetFrom)).Format(icsLocalDateTime))
+	w.raw("TZOFFSETFROM", formatOffset(offsetFrom))
+	w.raw("TZOFFSETTO", formatOffset(offset))
+	w.text("TZNAME", name)
+	w.raw("END", kind)
+}
+
+// formatOffset formats a UTC offset in seconds as ±HHMM, or ±HHMMSS for the
+// odd historical offsets that are not whole minutes.
+func formatOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	if seconds%60 != 0 {
+		return fmt.Sprintf("%s%02d%02d%02d", sign, seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds/60%60)
+}
+
+// writeTaskTime writes the start or end of a task, on the wall clock of zone
+// for recurring tasks when the feed has one.
+func writeTaskTime(w *icsWriter, name string, t tasks.TaskResponseDTO, at time.Time, zone *time.Location) {
+	if t.Recurrence != nil && zone != nil {
+		w.localTime(name, at, zone)
+		return
+	}
+	w.time(name, at)
+}
+
+func writeTaskEvent(w *icsWriter, t tasks.TaskResponseDTO, zone *time.Location, now time.Time) {
+	summary := t.Title
+	if t.FinishedAt != nil {
+		summary = "✓ " + summary
+	}
+
+	w.raw("BEGIN", "VEVENT")
+	w.raw("UID", "task-"+t.ID.String()+"@chronos")
+	w.time("DTSTAMP", now)
+	w.time("CREATED", t.CreatedAt)
+	writeTaskTime(w, "DTSTART", t, t.StartTime, zone)
+	writeTaskTime(w, "DTEND", t, *t.EndTime, zone)
+	w.text("SUMMARY", summary)
+	writeTaskDetails(w, t)
+	w.raw("STATUS", "CONFIRMED")
+	w.raw("END", "VEVENT")
+}
+
+func writeTaskTodo(w *icsWriter, t tasks.TaskResponseDTO, zone *time.Location, now time.Time) {
+	w.raw("BEGIN", "VTODO")
+	w.raw("UID", "task-"+t.ID.String()+"@chronos")
+	w.time("DTSTAMP", now)
+	w.time("CREATED", t.CreatedAt)
+	writeTaskTime(w, "DTSTART", t, t.StartTime, zone)
+	w.text("SUMMARY", t.Title)
+	writeTaskDetails(w, t)
+	if t.FinishedAt != nil {
+		w.raw("STATUS", "COMPLETED")
+		w.time("COMPLETED", *t.FinishedAt)
+		w.raw("PERCENT-COMPLETE", "100")
+	} else {
+		w.raw("STATUS", "NEEDS-ACTION")
+	}
+	w.raw("END", "VTODO")
+}
+
+func writeTaskDetails(w *icsWriter, t tasks.TaskResponseDTO) {
+	if t.Description != nil && *t.Description != "" {
+		w.text("DESCRIPTION", *t.Description)
+	}
+	if priority, ok := icsPriorities[t.Priority]; ok {
+		w.raw("PRIORITY", priority)
+	}
+	if t.Recurrence != nil {
+		w.raw("RRULE", *t.Recurrence)
+	}
+	w.text("X-CHRONOS-STATUS", string(t.Status))
+}
+
+func writeReviewEvent(w *icsWriter, p leetcode.ProblemResponseDTO, now time.Time) {
+	w.raw("BEGIN", "VEVENT")
+	w.raw("UID", "review-"+p.ID.String()+"@chronos")
+	w.time("DTSTAMP", now)
+	w.time("DTSTART", p.NextReview)
+	w.time("DTEND", p.NextReview.Add(reviewDuration))
+	w.text("SUMMARY", "Revisão: "+p.Title)
+	w.text("DESCRIPTION", string(p.Difficulty)+" · "+strings.Join(p.Tags, ", ")+"\n"+p.URL)
+	w.text("URL", p.URL)
+	w.text("CATEGORIES", "LeetCode")
+	w.raw("STATUS", "CONFIRMED")
+	w.raw("END", "VEVENT")
+}
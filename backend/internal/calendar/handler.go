@@ -0,0 +1,193 @@
+package calendar
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos/internal/shared/response"
+)
+
+const maxImportSize = 5 << 20 // 5MB
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// GetRange serves GET /calendar?from=&to=&tz=. Plain dates are interpreted in
+// the user's time zone, or in tz when given, and the to date is inclusive.
+func (h *Handler) GetRange(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	loc, err := h.service.Location(r.Context(), query.Get("tz"))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	from, _, err := parseBound(query.Get("from"), loc)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_FROM", "Parâmetro from inválido")
+		return
+	}
+
+	to, isDate, err := parseBound(query.Get("to"), loc)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_TO", "Parâmetro to inválido")
+		return
+	}
+	if isDate {
+		to = to.AddDate(0, 0, 1)
+	}
+
+	calendar, err := h.service.GetRange(r.Context(), from, to, loc)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, calendar)
+}
+
+func (h *Handler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	feed, err := h.service.GetFeed(r.Context())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, toFeedResponse(r, feed))
+}
+
+func (h *Handler) RotateFeed(w http.ResponseWriter, r *http.Request) {
+	feed, err := h.service.RotateFeed(r.Context())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, toFeedResponse(r, feed))
+}
+
+// Feed serves the public ICS document. It is authenticated only by the
+// secret token in the URL so calendar clients can subscribe to it.
+func (h *Handler) Feed(w http.ResponseWriter, r *http.Request) {
+	body, err := h.service.RenderFeed(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="chronos.ics"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(body))
+}
+
+// Import accepts an .ics file either as the "file" field of a multipart form
+// or as the raw request body. Query parameters: collection_id, dry_run, tz.
+func (h *Handler) Import(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	loc, err := h.service.Location(r.Context(), query.Get("tz"))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	opts := ImportOptions{
+		DryRun:   query.Get("dry_run") == "true",
+		Location: loc,
+	}
+
+	if value := query.Get("collection_id"); value != "" {
+		collectionID, err := uuid.Parse(value)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "INVALID_COLLECTION_ID", "ID de coleção inválido")
+			return
+		}
+		opts.CollectionID = &collectionID
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	var body io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(maxImportSize); err != nil {
+			response.Error(w, http.StatusBadRequest, "INVALID_PAYLOAD", "Erro ao processar formulário")
+			return
+		}
+
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "FILE_REQUIRED", "Arquivo .ics é obrigatório")
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	result, err := h.service.Import(r.Context(), body, opts)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if opts.DryRun {
+		status = http.StatusOK
+	}
+	response.JSON(w, status, result)
+}
+
+func (h *Handler) handleError(w http.ResponseWriter, err error) {
+	switch err {
+	case ErrInvalidRange:
+		response.Error(w, http.StatusBadRequest, "INVALID_RANGE", err.Error())
+	case ErrInvalidTimezone:
+		response.Error(w, http.StatusBadRequest, "INVALID_TIMEZONE", err.Error())
+	case ErrUnauthorized:
+		response.Error(w, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+	case ErrFeedNotFound:
+		response.Error(w, http.StatusNotFound, "FEED_NOT_FOUND", err.Error())
+	case ErrInvalidICS:
+		response.Error(w, http.StatusBadRequest, "INVALID_ICS", err.Error())
+	case ErrCollectionNotFound:
+		response.Error(w, http.StatusNotFound, "COLLECTION_NOT_FOUND", err.Error())
+	case ErrAlreadyImported:
+		response.Error(w, http.StatusConflict, "ALREADY_IMPORTED", err.Error())
+	default:
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Erro interno no servidor")
+	}
+}
+
+func parseBound(value string, loc *time.Location) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, false, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, loc)
+	return t, true, err
+}
+
+func toFeedResponse(r *http.Request, feed *FeedToken) FeedResponseDTO {
+	scheme := "https"
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	} else if r.TLS == nil && os.Getenv("RUN_MODE") == "local" {
+		scheme = "http"
+	}
+
+	return FeedResponseDTO{
+		URL:       fmt.Sprintf("%s://%s/api/v1/calendar/feed/%s.ics", scheme, r.Host, feed.Token),
+		UpdatedAt: feed.UpdatedAt,
+	}
+}
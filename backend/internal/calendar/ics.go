@@ -0,0 +1,282 @@
+package calendar
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	icsDateTime      = "20060102T150405Z"
+	icsLocalDateTime = "20060102T150405"
+	icsLineLength    = 75
+)
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// icsWriter builds an RFC 5545 document, taking care of CRLF line endings,
+// text escaping and folding of lines longer than 75 octets.
+type icsWriter struct {
+	b strings.Builder
+}
+
+func (w *icsWriter) raw(name, value string) {
+	line := name + ":" + value
+	limit := icsLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		w.b.WriteString(line[:cut])
+		w.b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts toward the limit.
+		limit = icsLineLength - 1
+	}
+	w.b.WriteString(line)
+	w.b.WriteString("\r\n")
+}
+
+func (w *icsWriter) text(name, value string) {
+	w.raw(name, icsEscaper.Replace(value))
+}
+
+func (w *icsWriter) time(name string, t time.Time) {
+	w.raw(name, t.UTC().Format(icsDateTime))
+}
+
+// localTime writes t on the wall clock of loc, whose VTIMEZONE must be part
+// of the document.
+func (w *icsWriter) localTime(name string, t time.Time, loc *time.Location) {
+	w.raw(name+";TZID="+loc.String(), t.In(loc).Format(icsLocalDateTime))
+}
+
+func (w *icsWriter) String() string {
+	return w.b.String()
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+type icsProperty struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// icsComponent is a VEVENT or VTODO with its own properties. Nested
+// components such as VALARM are skipped.
+type icsComponent struct {
+	Name       string
+	Properties []icsProperty
+}
+
+func (c *icsComponent) get(name string) (icsProperty, bool) {
+	for _, p := range c.Properties {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return icsProperty{}, false
+}
+
+func (c *icsComponent) text(name string) string {
+	p, ok := c.get(name)
+	if !ok {
+		return ""
+	}
+	return unescapeText(p.Value)
+}
+
+func parseICS(r io.Reader) ([]icsComponent, error) {
+	lines, err := unfoldLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		components []icsComponent
+		current    *icsComponent
+		depth      int
+		inCalendar bool
+	)
+
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		prop, err := parseProperty(line)
+		if err != nil {
+			return nil, err
+		}
+
+		switch prop.Name {
+		case "BEGIN":
+			value := strings.ToUpper(prop.Value)
+			switch {
+			case value == "VCALENDAR":
+				inCalendar = true
+			case current != nil:
+				depth++
+			case value == "VEVENT" || value == "VTODO":
+				current = &icsComponent{Name: value}
+			}
+		case "END":
+			value := strings.ToUpper(prop.Value)
+			switch {
+			case current != nil && depth > 0:
+				depth--
+			case current != nil && value == current.Name:
+				components = append(components, *current)
+				current = nil
+			}
+		default:
+			if current != nil && depth == 0 {
+				current.Properties = append(current.Properties, prop)
+			}
+		}
+	}
+
+	if !inCalendar || current != nil {
+		return nil, ErrInvalidICS
+	}
+	return components, nil
+}
+
+func unfoldLines(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, ErrInvalidICS
+	}
+	return lines, nil
+}
+
+// parseProperty splits "NAME;PARAM=value:VALUE", honoring quoted parameter
+// values that may contain ':' or ';'.
+func parseProperty(line string) (icsProperty, error) {
+	inQuotes := false
+	colon := -1
+	for i, c := range line {
+		if c == '"' {
+			inQuotes = !inQuotes
+		} else if c == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon <= 0 {
+		return icsProperty{}, ErrInvalidICS
+	}
+
+	head := strings.Split(line[:colon], ";")
+	prop := icsProperty{
+		Name:   strings.ToUpper(head[0]),
+		Params: make(map[string]string, len(head)-1),
+		Value:  line[colon+1:],
+	}
+	for _, param := range head[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		prop.Params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	return prop, nil
+}
+
+func unescapeText(value string) string {
+	return strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n").Replace(value)
+}
+
+// parseICSTime parses DATE and DATE-TIME values. Floating times and times
+// with an unknown TZID are interpreted in loc.
+func parseICSTime(prop icsProperty, loc *time.Location) (time.Time, error) {
+	value := prop.Value
+
+	if prop.Params["VALUE"] == "DATE" || len(value) == 8 {
+		return time.ParseInLocation("20060102", value, loc)
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		return time.Parse(icsDateTime, value)
+	}
+
+	if tzid := prop.Params["TZID"]; tzid != "" {
+		if tz, err := time.LoadLocation(tzid); err == nil {
+			loc = tz
+		}
+	}
+	return time.ParseInLocation("20060102T150405", value, loc)
+}
+
+// parseICSDuration parses RFC 5545 durations such as PT1H30M, P1D or -P2W.
+func parseICSDuration(value string) (time.Duration, error) {
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(value, "-"):
+		sign = -1
+		value = value[1:]
+	case strings.HasPrefix(value, "+"):
+		value = value[1:]
+	}
+
+	if !strings.HasPrefix(value, "P") || len(value) < 3 {
+		return 0, ErrInvalidICS
+	}
+	value = value[1:]
+
+	var (
+		total  time.Duration
+		number int
+		digits bool
+		inTime bool
+	)
+	for _, c := range value {
+		switch {
+		case c >= '0' && c <= '9':
+			number = number*10 + int(c-'0')
+			digits = true
+			continue
+		case c == 'T':
+			inTime = true
+			continue
+		}
+
+		if !digits {
+			return 0, ErrInvalidICS
+		}
+
+		switch {
+		case c == 'W' && !inTime:
+			total += time.Duration(number) * 7 * 24 * time.Hour
+		case c == 'D' && !inTime:
+			total += time.Duration(number) * 24 * time.Hour
+		case c == 'H' && inTime:
+			total += time.Duration(number) * time.Hour
+		case c == 'M' && inTime:
+			total += time.Duration(number) * time.Minute
+		case c == 'S' && inTime:
+			total += time.Duration(number) * time.Second
+		default:
+			return 0, ErrInvalidICS
+		}
+		number, digits = 0, false
+	}
+
+	if digits {
+		return 0, ErrInvalidICS
+	}
+	return sign * total, nil
+}
@@ -0,0 +1,127 @@
+package calendar
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos/internal/tasks"
+)
+
+type ImportAction string
+
+const (
+	ImportCreate    ImportAction = "CREATE"
+	ImportDuplicate ImportAction = "DUPLICATE"
+	ImportSkip      ImportAction = "SKIP"
+)
+
+type ImportOptions struct {
+	CollectionID *uuid.UUID
+	DryRun       bool
+	Location     *time.Location
+}
+
+// toCreateTaskDTO maps a VEVENT/VTODO to a task. It returns a non-empty
+// reason when the component cannot be imported and an optional warning when
+// part of it had to be dropped.
+func toCreateTaskDTO(c icsComponent, opts ImportOptions) (dto tasks.CreateTaskDTO, reason, warning string) {
+	dto = tasks.CreateTaskDTO{
+		Title:        strings.TrimSpace(c.text("SUMMARY")),
+		Status:       tasks.Pending,
+		Priority:     mapPriority(c.text("PRIORITY")),
+		CollectionID: opts.CollectionID,
+	}
+	if dto.Title == "" {
+		dto.Title = "Sem título"
+	}
+	if description := strings.TrimSpace(c.text("DESCRIPTION")); description != "" {
+		dto.Description = &description
+	}
+	if uid := strings.TrimSpace(c.text("UID")); uid != "" {
+		dto.ExternalUID = &uid
+	}
+
+	switch strings.ToUpper(c.text("STATUS")) {
+	case "CANCELLED":
+		return dto, "componente cancelado", ""
+	case "COMPLETED":
+		dto.Status = tasks.Done
+	}
+
+	start, hasStart, err := componentTime(c, "DTSTART", opts.Location)
+	if err != nil {
+		return dto, "DTSTART inválido", ""
+	}
+
+	endProperty := "DTEND"
+	if c.Name == "VTODO" {
+		endProperty = "DUE"
+	}
+	end, hasEnd, err := componentTime(c, endProperty, opts.Location)
+	if err != nil {
+		return dto, endProperty + " inválido", ""
+	}
+
+	if !hasEnd && hasStart {
+		if p, ok := c.get("DURATION"); ok {
+			duration, err := parseICSDuration(p.Value)
+			if err != nil {
+				return dto, "DURATION inválido", ""
+			}
+			end, hasEnd = start.Add(duration), true
+		}
+	}
+
+	switch {
+	case hasStart:
+		dto.StartTime = start
+	case hasEnd:
+		dto.StartTime = end
+	default:
+		return dto, "sem DTSTART", ""
+	}
+	if hasEnd && end.After(dto.StartTime) {
+		dto.EndTime = &end
+	}
+
+	if p, ok := c.get("RRULE"); ok {
+		if rule, err := tasks.ParseRecurrenceRule(p.Value); err == nil {
+			normalized := rule.String()
+			dto.Recurrence = &normalized
+		} else {
+			warning = "RRULE não suportada, importada sem recorrência"
+		}
+	}
+
+	return dto, "", warning
+}
+
+func componentTime(c icsComponent, name string, loc *time.Location) (time.Time, bool, error) {
+	p, ok := c.get(name)
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	t, err := parseICSTime(p, loc)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return t, true, nil
+}
+
+// mapPriority converts the 0-9 iCalendar priority (1 highest, 0 undefined)
+// to a task priority.
+func mapPriority(value string) tasks.Priority {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	switch {
+	case err != nil || n == 0:
+		return tasks.Medium
+	case n <= 4:
+		return tasks.High
+	case n == 5:
+		return tasks.Medium
+	default:
+		return tasks.Low
+	}
+}
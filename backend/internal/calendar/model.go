@@ -0,0 +1,19 @@
+package calendar
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FeedToken is the secret that grants read-only access to a user's ICS feed.
+type FeedToken struct {
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey"`
+	Token     string    `json:"-" gorm:"uniqueIndex;not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (FeedToken) TableName() string {
+	return "calendar_feed_tokens"
+}
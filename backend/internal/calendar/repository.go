@@ -0,0 +1,54 @@
+package calendar
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	GetFeedByUserID(ctx context.Context, userID uuid.UUID) (*FeedToken, error)
+	GetFeedByToken(ctx context.Context, token string) (*FeedToken, error)
+	SaveFeed(ctx context.Context, feed *FeedToken) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) GetFeedByUserID(ctx context.Context, userID uuid.UUID) (*FeedToken, error) {
+	var feed FeedToken
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		First(&feed).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &feed, nil
+}
+
+func (r *repository) GetFeedByToken(ctx context.Context, token string) (*FeedToken, error) {
+	var feed FeedToken
+
+	err := r.db.WithContext(ctx).
+		Where("token = ?", token).
+		First(&feed).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &feed, nil
+}
+
+func (r *repository) SaveFeed(ctx context.Context, feed *FeedToken) error {
+	return r.db.WithContext(ctx).Save(feed).Error
+}
@@ -0,0 +1,310 @@
+package calendar
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/saulo-duarte/chronos/internal/auth"
+	"github.com/saulo-duarte/chronos/internal/collections"
+	"github.com/saulo-duarte/chronos/internal/leetcode"
+	sharedauth "github.com/saulo-duarte/chronos/internal/shared/auth"
+	"github.com/saulo-duarte/chronos/internal/shared/middlewares"
+	"github.com/saulo-duarte/chronos/internal/tasks"
+	"gorm.io/gorm"
+)
+
+const maxRange = 366 * 24 * time.Hour
+
+type Service interface {
+	Location(ctx context.Context, name string) (*time.Location, error)
+	GetRange(ctx context.Context, from, to time.Time, loc *time.Location) (*CalendarResponseDTO, error)
+	GetFeed(ctx context.Context) (*FeedToken, error)
+	RotateFeed(ctx context.Context) (*FeedToken, error)
+	RenderFeed(ctx context.Context, token string) (string, error)
+	Import(ctx context.Context, body io.Reader, opts ImportOptions) (*ImportResultDTO, error)
+}
+
+type service struct {
+	repository  Repository
+	tasks       tasks.Service
+	leetcode    leetcode.Service
+	collections collections.Service
+	locations   auth.Locator
+}
+
+func NewService(repository Repository, taskService tasks.Service, leetcodeService leetcode.Service, collectionService collections.Service, locator auth.Locator) Service {
+	return &service{
+		repository:  repository,
+		tasks:       taskService,
+		leetcode:    leetcodeService,
+		collections: collectionService,
+		locations:   locator,
+	}
+}
+
+// Location resolves the time zone of a request: the named one when the
+// client overrides it, the user's own otherwise.
+func (s *service) Location(ctx context.Context, name string) (*time.Location, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	if name == "" {
+		return s.locations.Location(ctx, userID)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+	return loc, nil
+}
+
+// GetRange merges task occurrences and scheduled LeetCode reviews within
+// [from, to) into a single timeline grouped by day in loc, which is also
+// the zone recurring tasks are expanded in.
+func (s *service) GetRange(ctx context.Context, from, to time.Time, loc *time.Location) (*CalendarResponseDTO, error) {
+	if _, err := middlewares.GetUserIDFromContext(ctx); err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	if !to.After(from) || to.Sub(from) > maxRange {
+		return nil, ErrInvalidRange
+	}
+
+	occurrences, err := s.tasks.GetOccurrences(ctx, from, to, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, err := s.leetcode.GetScheduledBetween(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]EventDTO, 0, len(occurrences)+len(reviews))
+	for _, o := range occurrences {
+		events = append(events, fromOccurrence(o, loc))
+	}
+	for _, p := range reviews {
+		events = append(events, fromReview(p, loc))
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Start.Before(events[j].Start)
+	})
+
+	return &CalendarResponseDTO{
+		From:     from.In(loc),
+		To:       to.In(loc),
+		Timezone: loc.String(),
+		Days:     groupByDay(events, from.In(loc)),
+	}, nil
+}
+
+// groupByDay buckets the sorted events by their local date. Events that
+// started before the range (e.g. multi-day tasks) are placed on its first day.
+func groupByDay(events []EventDTO, rangeStart time.Time) []DayDTO {
+	days := []DayDTO{}
+	first := rangeStart.Format("2006-01-02")
+
+	for _, event := range events {
+		date := event.Start.Format("2006-01-02")
+		if date < first {
+			date = first
+		}
+
+		if len(days) == 0 || days[len(days)-1].Date != date {
+			days = append(days, DayDTO{Date: date})
+		}
+		days[len(days)-1].Events = append(days[len(days)-1].Events, event)
+	}
+
+	return days
+}
+
+// GetFeed returns the feed token of the user, creating one on first use.
+func (s *service) GetFeed(ctx context.Context) (*FeedToken, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	feed, err := s.repository.GetFeedByUserID(ctx, userID)
+	if err == nil {
+		return feed, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	return s.RotateFeed(ctx)
+}
+
+// RotateFeed replaces the feed token, invalidating previously shared URLs.
+func (s *service) RotateFeed(ctx context.Context) (*FeedToken, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	token, err := generateFeedToken()
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &FeedToken{UserID: userID, Token: token}
+	if existing, err := s.repository.GetFeedByUserID(ctx, userID); err == nil {
+		feed.CreatedAt = existing.CreatedAt
+	}
+
+	if err := s.repository.SaveFeed(ctx, feed); err != nil {
+		return nil, err
+	}
+
+	return feed, nil
+}
+
+// RenderFeed authenticates the request by its feed token and renders the
+// owner's tasks and review schedule as an iCalendar document.
+func (s *service) RenderFeed(ctx context.Context, token string) (string, error) {
+	feed, err := s.repository.GetFeedByToken(ctx, token)
+	if err != nil {
+		return "", ErrFeedNotFound
+	}
+
+	ctx = context.WithValue(ctx, sharedauth.UserContextKey, feed.UserID)
+
+	taskList, err := s.tasks.GetAllByUserID(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	problems, err := s.leetcode.GetAll(ctx, leetcode.ProblemFilter{})
+	if err != nil {
+		return "", err
+	}
+
+	loc, err := s.locations.Location(ctx, feed.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	return renderFeed(taskList, problems, loc, time.Now()), nil
+}
+
+// Import parses an iCalendar file and creates one task per VEVENT/VTODO.
+// Components whose UID was already imported, or that repeat a UID within the
+// file, are reported as duplicates. In dry-run mode nothing is written.
+func (s *service) Import(ctx context.Context, body io.Reader, opts ImportOptions) (*ImportResultDTO, error) {
+	if _, err := middlewares.GetUserIDFromContext(ctx); err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+
+	if opts.CollectionID != nil {
+		if _, err := s.collections.GetByID(ctx, *opts.CollectionID); err != nil {
+			return nil, ErrCollectionNotFound
+		}
+	}
+
+	components, err := parseICS(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var uids []string
+	for _, c := range components {
+		if uid := strings.TrimSpace(c.text("UID")); uid != "" {
+			uids = append(uids, uid)
+		}
+	}
+
+	existing, err := s.tasks.ExistingExternalUIDs(ctx, uids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResultDTO{DryRun: opts.DryRun, Items: make([]ImportItemDTO, 0, len(components))}
+	var (
+		toCreate []tasks.CreateTaskDTO
+		created  []int
+	)
+
+	for _, c := range components {
+		dto, reason, warning := toCreateTaskDTO(c, opts)
+		item := ImportItemDTO{
+			Component:  c.Name,
+			Title:      dto.Title,
+			Status:     dto.Status,
+			Priority:   dto.Priority,
+			Recurrence: dto.Recurrence,
+			Action:     ImportCreate,
+			Reason:     reason,
+			Warning:    warning,
+		}
+		if !dto.StartTime.IsZero() {
+			start := dto.StartTime
+			item.StartTime = &start
+			item.EndTime = dto.EndTime
+		}
+		if dto.ExternalUID != nil {
+			item.UID = *dto.ExternalUID
+		}
+
+		switch {
+		case reason != "":
+			item.Action = ImportSkip
+			result.Skipped++
+		case item.UID != "" && existing[item.UID]:
+			item.Action = ImportDuplicate
+			item.Reason = "UID já importado"
+			result.Duplicates++
+		default:
+			if item.UID != "" {
+				existing[item.UID] = true
+			}
+			toCreate = append(toCreate, dto)
+			created = append(created, len(result.Items))
+			result.Created++
+		}
+
+		result.Items = append(result.Items, item)
+	}
+
+	if opts.DryRun || len(toCreate) == 0 {
+		return result, nil
+	}
+
+	responses, err := s.tasks.CreateMany(ctx, toCreate)
+	if err == tasks.ErrAlreadyImported {
+		return nil, ErrAlreadyImported
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, response := range responses {
+		id := response.ID
+		result.Items[created[i]].TaskID = &id
+	}
+
+	return result, nil
+}
+
+func generateFeedToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
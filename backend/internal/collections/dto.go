@@ -21,6 +21,26 @@ type UpdateCollectionDTO struct {
 	IsArchived  *bool   `json:"is_archived,omitempty"`
 }
 
+type WorkflowColumnDTO struct {
+	Key                string   `json:"key" validate:"required"`
+	Name               string   `json:"name" validate:"required,max=50"`
+	IsTerminal         bool     `json:"is_terminal"`
+	AllowedTransitions []string `json:"allowed_transitions,omitempty"`
+}
+
+type UpdateWorkflowDTO struct {
+	Columns []WorkflowColumnDTO `json:"columns"`
+}
+
+type WorkflowColumnResponseDTO struct {
+	ID                 uuid.UUID `json:"id"`
+	Key                string    `json:"key"`
+	Name               string    `json:"name"`
+	Position           int       `json:"position"`
+	IsTerminal         bool      `json:"is_terminal"`
+	AllowedTransitions []string  `json:"allowed_transitions"`
+}
+
 type CollectionResponseDTO struct {
 	ID          uuid.UUID `json:"id"`
 	Title       string    `json:"title"`
@@ -61,3 +81,22 @@ func ToResponseList(collections []Collection) []CollectionResponseDTO {
 	}
 	return responses
 }
+
+func ToWorkflowResponse(columns []WorkflowColumn) []WorkflowColumnResponseDTO {
+	responses := make([]WorkflowColumnResponseDTO, len(columns))
+	for i, c := range columns {
+		transitions := c.AllowedTransitions
+		if transitions == nil {
+			transitions = []string{}
+		}
+		responses[i] = WorkflowColumnResponseDTO{
+			ID:                 c.ID,
+			Key:                c.Key,
+			Name:               c.Name,
+			Position:           c.Position,
+			IsTerminal:         c.IsTerminal,
+			AllowedTransitions: transitions,
+		}
+	}
+	return responses
+}
@@ -5,4 +5,5 @@ import "errors"
 var (
 	ErrCollectionNotFound = errors.New("collection not found")
 	ErrUnauthorized       = errors.New("unauthorized")
+	ErrInvalidWorkflow    = errors.New("workflow needs unique column keys, at least one open and one terminal column, and transitions to existing columns")
 )
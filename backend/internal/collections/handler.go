@@ -96,12 +96,52 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusOK, map[string]string{"message": "Coleção excluída com sucesso"})
 }
 
+func (h *Handler) GetWorkflow(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	workflow, err := h.service.GetWorkflow(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, workflow)
+}
+
+func (h *Handler) UpdateWorkflow(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	var dto UpdateWorkflowDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_PAYLOAD", "Payload inválido")
+		return
+	}
+
+	workflow, err := h.service.UpdateWorkflow(r.Context(), id, &dto)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, workflow)
+}
+
 func (h *Handler) handleError(w http.ResponseWriter, err error) {
 	switch err {
 	case ErrCollectionNotFound:
 		response.Error(w, http.StatusNotFound, "COLLECTION_NOT_FOUND", err.Error())
 	case ErrUnauthorized:
 		response.Error(w, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+	case ErrInvalidWorkflow:
+		response.Error(w, http.StatusBadRequest, "INVALID_WORKFLOW", err.Error())
 	default:
 		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Erro interno no servidor")
 	}
@@ -25,3 +25,25 @@ func (c *Collection) BeforeCreate(tx *gorm.DB) (err error) {
 	}
 	return
 }
+
+// WorkflowColumn is one stage of a collection's kanban workflow. Its Key is
+// the task status used by tasks in that column.
+type WorkflowColumn struct {
+	ID                 uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	CollectionID       uuid.UUID `json:"collection_id" gorm:"type:uuid;not null;uniqueIndex:idx_workflow_column_key"`
+	UserID             uuid.UUID `json:"user_id" gorm:"type:uuid;index;not null"`
+	Key                string    `json:"key" gorm:"not null;uniqueIndex:idx_workflow_column_key"`
+	Name               string    `json:"name" gorm:"not null"`
+	Position           int       `json:"position" gorm:"not null"`
+	IsTerminal         bool      `json:"is_terminal" gorm:"default:false"`
+	AllowedTransitions []string  `json:"allowed_transitions" gorm:"serializer:json"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+func (c *WorkflowColumn) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return
+}
@@ -13,6 +13,8 @@ type Repository interface {
 	GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]Collection, error)
 	Update(ctx context.Context, collection *Collection) error
 	Delete(ctx context.Context, id, userID uuid.UUID) error
+	GetWorkflow(ctx context.Context, collectionID, userID uuid.UUID) ([]WorkflowColumn, error)
+	ReplaceWorkflow(ctx context.Context, collectionID, userID uuid.UUID, columns []WorkflowColumn) error
 }
 
 type repository struct {
@@ -54,7 +56,34 @@ func (r *repository) Update(ctx context.Context, collection *Collection) error {
 }
 
 func (r *repository) Delete(ctx context.Context, id, userID uuid.UUID) error {
-	return r.db.WithContext(ctx).
-		Where("id = ? AND user_id = ?", id, userID).
-		Delete(&Collection{}).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("collection_id = ? AND user_id = ?", id, userID).Delete(&WorkflowColumn{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ? AND user_id = ?", id, userID).Delete(&Collection{}).Error
+	})
+}
+
+func (r *repository) GetWorkflow(ctx context.Context, collectionID, userID uuid.UUID) ([]WorkflowColumn, error) {
+	var columns []WorkflowColumn
+	err := r.db.WithContext(ctx).
+		Where("collection_id = ? AND user_id = ?", collectionID, userID).
+		Order("position ASC").
+		Find(&columns).Error
+	if err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
+
+func (r *repository) ReplaceWorkflow(ctx context.Context, collectionID, userID uuid.UUID, columns []WorkflowColumn) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("collection_id = ? AND user_id = ?", collectionID, userID).Delete(&WorkflowColumn{}).Error; err != nil {
+			return err
+		}
+		if len(columns) == 0 {
+			return nil
+		}
+		return tx.Create(&columns).Error
+	})
 }
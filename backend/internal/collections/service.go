@@ -2,6 +2,8 @@ package collections
 
 import (
 	"context"
+	"regexp"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/saulo-duarte/chronos/internal/shared/middlewares"
@@ -13,8 +15,12 @@ type Service interface {
 	GetAll(ctx context.Context) ([]CollectionResponseDTO, error)
 	Update(ctx context.Context, id uuid.UUID, dto *UpdateCollectionDTO) (*CollectionResponseDTO, error)
 	Delete(ctx context.Context, id uuid.UUID) error
+	GetWorkflow(ctx context.Context, id uuid.UUID) ([]WorkflowColumnResponseDTO, error)
+	UpdateWorkflow(ctx context.Context, id uuid.UUID, dto *UpdateWorkflowDTO) ([]WorkflowColumnResponseDTO, error)
 }
 
+var workflowKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,39}$`)
+
 type service struct {
 	repository Repository
 }
@@ -115,3 +121,95 @@ func (s *service) Delete(ctx context.Context, id uuid.UUID) error {
 
 	return s.repository.Delete(ctx, id, userID)
 }
+
+func (s *service) GetWorkflow(ctx context.Context, id uuid.UUID) ([]WorkflowColumnResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	if _, err := s.repository.GetByID(ctx, id, userID); err != nil {
+		return nil, ErrCollectionNotFound
+	}
+
+	columns, err := s.repository.GetWorkflow(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToWorkflowResponse(columns), nil
+}
+
+// UpdateWorkflow replaces the ordered columns of the collection. An empty
+// list restores the default PENDING/DONE workflow. Tasks whose status no
+// longer matches a column keep it until they are moved.
+func (s *service) UpdateWorkflow(ctx context.Context, id uuid.UUID, dto *UpdateWorkflowDTO) ([]WorkflowColumnResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	if _, err := s.repository.GetByID(ctx, id, userID); err != nil {
+		return nil, ErrCollectionNotFound
+	}
+
+	columns, err := buildWorkflow(id, userID, dto.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.ReplaceWorkflow(ctx, id, userID, columns); err != nil {
+		return nil, err
+	}
+
+	return ToWorkflowResponse(columns), nil
+}
+
+func buildWorkflow(collectionID, userID uuid.UUID, dtos []WorkflowColumnDTO) ([]WorkflowColumn, error) {
+	if len(dtos) == 0 {
+		return nil, nil
+	}
+
+	keys := make(map[string]bool, len(dtos))
+	hasOpen, hasTerminal := false, false
+	for _, c := range dtos {
+		key := strings.ToUpper(strings.TrimSpace(c.Key))
+		if !workflowKeyPattern.MatchString(key) || keys[key] || strings.TrimSpace(c.Name) == "" {
+			return nil, ErrInvalidWorkflow
+		}
+		keys[key] = true
+		if c.IsTerminal {
+			hasTerminal = true
+		} else {
+			hasOpen = true
+		}
+	}
+	if !hasOpen || !hasTerminal {
+		return nil, ErrInvalidWorkflow
+	}
+
+	columns := make([]WorkflowColumn, len(dtos))
+	for i, c := range dtos {
+		transitions := make([]string, 0, len(c.AllowedTransitions))
+		for _, t := range c.AllowedTransitions {
+			t = strings.ToUpper(strings.TrimSpace(t))
+			if !keys[t] {
+				return nil, ErrInvalidWorkflow
+			}
+			transitions = append(transitions, t)
+		}
+
+		columns[i] = WorkflowColumn{
+			ID:                 uuid.New(),
+			CollectionID:       collectionID,
+			UserID:             userID,
+			Key:                strings.ToUpper(strings.TrimSpace(c.Key)),
+			Name:               strings.TrimSpace(c.Name),
+			Position:           i,
+			IsTerminal:         c.IsTerminal,
+			AllowedTransitions: transitions,
+		}
+	}
+
+	return columns, nil
+}
@@ -1,18 +1,24 @@
 package container
 
 import (
+	"context"
 	"log"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/saulo-duarte/chronos/internal/auth"
+	"github.com/saulo-duarte/chronos/internal/calendar"
 	"github.com/saulo-duarte/chronos/internal/collections"
 	"github.com/saulo-duarte/chronos/internal/leetcode"
+	"github.com/saulo-duarte/chronos/internal/pomodoro"
 	"github.com/saulo-duarte/chronos/internal/resources"
 	sharedauth "github.com/saulo-duarte/chronos/internal/shared/auth"
 	"github.com/saulo-duarte/chronos/internal/shared/config"
+	"github.com/saulo-duarte/chronos/internal/shared/jobs"
 	"github.com/saulo-duarte/chronos/internal/shared/storage"
 	"github.com/saulo-duarte/chronos/internal/tasks"
+	"github.com/saulo-duarte/chronos/internal/templates"
 	"github.com/saulo-duarte/chronos/internal/objectives"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -28,7 +34,11 @@ type Container struct {
 	ResourceHandler   *resources.Handler
 	LeetCodeHandler   *leetcode.Handler
 	ObjectiveHandler  *objectives.Handler
+	CalendarHandler   *calendar.Handler
+	PomodoroHandler   *pomodoro.Handler
+	TemplateHandler   *templates.Handler
 	JWTService        *sharedauth.TokenService
+	Jobs              *jobs.Runner
 }
 
 func New() *Container {
@@ -62,22 +72,111 @@ func New() *Container {
 		log.Fatalf("Falha ao migrar Collection: %v", err)
 	}
 
+	if err := db.AutoMigrate(&collections.WorkflowColumn{}); err != nil {
+		log.Fatalf("Falha ao migrar WorkflowColumn: %v", err)
+	}
+
 	if err := db.AutoMigrate(&tasks.Task{}); err != nil {
 		log.Fatalf("Falha ao migrar Task: %v", err)
 	}
 
+	if db.Migrator().HasIndex(&tasks.Task{}, "idx_tasks_external_uid") {
+		if err := db.Migrator().DropIndex(&tasks.Task{}, "idx_tasks_external_uid"); err != nil {
+			log.Fatalf("Falha ao remover índice idx_tasks_external_uid: %v", err)
+		}
+	}
+
+	if err := db.AutoMigrate(&tasks.TaskOccurrence{}); err != nil {
+		log.Fatalf("Falha ao migrar TaskOccurrence: %v", err)
+	}
+
+	if err := db.AutoMigrate(&tasks.TimeEntry{}); err != nil {
+		log.Fatalf("Falha ao migrar TimeEntry: %v", err)
+	}
+
+	if err := db.AutoMigrate(&tasks.TaskComment{}); err != nil {
+		log.Fatalf("Falha ao migrar TaskComment: %v", err)
+	}
+
+	if err := db.AutoMigrate(&tasks.TaskDependency{}); err != nil {
+		log.Fatalf("Falha ao migrar TaskDependency: %v", err)
+	}
+
+	if err := db.AutoMigrate(&tasks.RolloverSettings{}); err != nil {
+		log.Fatalf("Falha ao migrar RolloverSettings: %v", err)
+	}
+
+	if err := db.AutoMigrate(&tasks.RolloverLog{}); err != nil {
+		log.Fatalf("Falha ao migrar RolloverLog: %v", err)
+	}
+
+	if err := db.AutoMigrate(&templates.Template{}); err != nil {
+		log.Fatalf("Falha ao migrar Template: %v", err)
+	}
+
+	if err := db.AutoMigrate(&templates.TemplateItem{}); err != nil {
+		log.Fatalf("Falha ao migrar TemplateItem: %v", err)
+	}
+
 	if err := db.AutoMigrate(&resources.Resource{}); err != nil {
 		log.Fatalf("Falha ao migrar Resource: %v", err)
 	}
 
+	if err := db.AutoMigrate(&leetcode.Tag{}); err != nil {
+		log.Fatalf("Falha ao migrar Tag: %v", err)
+	}
+
 	if err := db.AutoMigrate(&leetcode.LeetCodeProblem{}); err != nil {
 		log.Fatalf("Falha ao migrar LeetCodeProblem: %v", err)
 	}
 
+	if err := leetcode.MigrateTags(db); err != nil {
+		log.Fatalf("Falha ao migrar padrões para tags: %v", err)
+	}
+
+	if err := db.AutoMigrate(&leetcode.ReviewSettings{}); err != nil {
+		log.Fatalf("Falha ao migrar ReviewSettings: %v", err)
+	}
+
+	if err := db.AutoMigrate(&leetcode.ReviewSession{}); err != nil {
+		log.Fatalf("Falha ao migrar ReviewSession: %v", err)
+	}
+
+	if err := db.AutoMigrate(&leetcode.ReviewLog{}); err != nil {
+		log.Fatalf("Falha ao migrar ReviewLog: %v", err)
+	}
+
+	// The time zone used to be saved with the rollover and review settings.
+	// Carry it over to users that have not set one before dropping the old
+	// columns.
+	for _, table := range []string{"task_rollover_settings", "leetcode_review_settings"} {
+		if !db.Migrator().HasColumn(table, "timezone") {
+			continue
+		}
+		if err := db.Exec("UPDATE users SET timezone = s.timezone FROM " + table + " s WHERE s.user_id = users.id AND users.timezone = 'UTC'").Error; err != nil {
+			log.Fatalf("Falha ao migrar fuso horário de %s: %v", table, err)
+		}
+		if err := db.Migrator().DropColumn(table, "timezone"); err != nil {
+			log.Fatalf("Falha ao remover fuso horário de %s: %v", table, err)
+		}
+	}
+
 	if err := db.AutoMigrate(&objectives.Objective{}); err != nil {
 		log.Fatalf("Falha ao migrar Objective: %v", err)
 	}
 
+	if err := db.AutoMigrate(&calendar.FeedToken{}); err != nil {
+		log.Fatalf("Falha ao migrar FeedToken: %v", err)
+	}
+
+	if err := db.AutoMigrate(&pomodoro.Settings{}); err != nil {
+		log.Fatalf("Falha ao migrar PomodoroSettings: %v", err)
+	}
+
+	if err := db.AutoMigrate(&pomodoro.Session{}); err != nil {
+		log.Fatalf("Falha ao migrar PomodoroSession: %v", err)
+	}
+
 	jwtSvc := sharedauth.NewTokenService(cfg.JWTSecret)
 	storageSvc := storage.NewClient(
 		cfg.StorageURL,
@@ -87,11 +186,36 @@ func New() *Container {
 	)
 
 	authContainer := auth.NewContainer(db, cfg, jwtSvc)
-	tasksContainer := tasks.NewContainer(db)
 	collectionsContainer := collections.NewContainer(db)
+	tasksContainer := tasks.NewContainer(db, collectionsContainer.Repository, authContainer.Repository)
 	resourcesContainer := resources.NewContainer(db, storageSvc)
-	leetcodeContainer := leetcode.NewContainer(db)
+	leetcodeContainer := leetcode.NewContainer(db, cfg, authContainer.Repository)
 	objectivesContainer := objectives.NewContainer(db)
+	calendarContainer := calendar.NewContainer(db, tasksContainer.Service, leetcodeContainer.Service, collectionsContainer.Service, authContainer.Repository)
+	pomodoroContainer := pomodoro.NewContainer(db, tasksContainer.Repository, collectionsContainer.Repository, authContainer.Repository)
+	templatesContainer := templates.NewContainer(db, tasksContainer.Service, collectionsContainer.Repository, authContainer.Repository)
+
+	jobRunner := jobs.NewRunner(
+		jobs.Job{
+			Name:     "task-rollover",
+			Interval: time.Hour,
+			Run: func(ctx context.Context) error {
+				moved, err := tasksContainer.Service.ApplyRollover(ctx, time.Now())
+				log.Printf("Rollover moveu %d tarefas atrasadas", moved)
+				return err
+			},
+		},
+		jobs.Job{
+			Name:     "task-trash-purge",
+			Interval: 24 * time.Hour,
+			Run: func(ctx context.Context) error {
+				before := time.Now().AddDate(0, 0, -cfg.TaskTrashRetentionDays)
+				purged, err := tasksContainer.Service.PurgeTrash(ctx, before)
+				log.Printf("Lixeira removeu %d tarefas definitivamente", purged)
+				return err
+			},
+		},
+	)
 
 	return &Container{
 		Config:            cfg,
@@ -103,6 +227,10 @@ func New() *Container {
 		ResourceHandler:   resourcesContainer.Handler,
 		LeetCodeHandler:   leetcodeContainer.Handler,
 		ObjectiveHandler:  objectivesContainer.Handler,
+		CalendarHandler:   calendarContainer.Handler,
+		PomodoroHandler:   pomodoroContainer.Handler,
+		TemplateHandler:   templatesContainer.Handler,
 		JWTService:        jwtSvc,
+		Jobs:              jobRunner,
 	}
 }
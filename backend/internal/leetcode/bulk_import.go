@@ -0,0 +1,354 @@
+package leetcode
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+type ImportAction string
+
+const (
+	ImportCreate    ImportAction = "CREATE"
+	ImportDuplicate ImportAction = "DUPLICATE"
+	ImportSkip      ImportAction = "SKIP"
+)
+
+type ImportFormat string
+
+const (
+	ImportCSV  ImportFormat = "csv"
+	ImportJSON ImportFormat = "json"
+)
+
+// defaultConfidence is used for rows without a confidence when the request
+// does not choose another default.
+const defaultConfidence = 3
+
+type BulkImportOptions struct {
+	Format     ImportFormat
+	DryRun     bool
+	Confidence int
+}
+
+// importRow is one problem of an import file before validation. Line is the
+// line of the row in a CSV file or its position in a JSON array; Reason is
+// set when the row could not even be read.
+type importRow struct {
+	Line        int
+	Reason      string
+	Title       string
+	URL         string
+	Slug        string
+	Pattern     string
+	Tags        []string
+	Difficulty  string
+	Confidence  string
+	InsightNote string
+	SolvedAt    string
+}
+
+// jsonImportRow accepts our own field names and the camelCase ones of
+// LeetCode's submission data, so an export can be posted as is.
+type jsonImportRow struct {
+	Title       string          `json:"title"`
+	URL         string          `json:"url"`
+	Slug        string          `json:"slug"`
+	TitleSlug   string          `json:"titleSlug"`
+	Pattern     string          `json:"pattern"`
+	Tags        []string        `json:"tags"`
+	Difficulty  string          `json:"difficulty"`
+	Confidence  json.RawMessage `json:"confidence"`
+	InsightNote string          `json:"insight_note"`
+	SolvedAt    string          `json:"solved_at"`
+	Timestamp   json.RawMessage `json:"timestamp"`
+}
+
+func parseImportRows(body io.Reader, format ImportFormat) ([]importRow, error) {
+	switch format {
+	case ImportCSV:
+		return parseCSVRows(body)
+	case ImportJSON:
+		return parseJSONRows(body)
+	}
+	return nil, ErrInvalidImportFormat
+}
+
+// parseCSVRows reads a CSV file with a header row. Columns are matched by
+// name in any order and unknown columns are ignored; title, difficulty,
+// pattern or tags (separated by ";") and url or slug are required.
+func parseCSVRows(body io.Reader) ([]importRow, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, ErrInvalidImportFile
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[strings.ReplaceAll(name, " ", "_")] = i
+	}
+	for _, required := range []string{"title", "difficulty"} {
+		if _, ok := columns[required]; !ok {
+			return nil, ErrInvalidImportFile
+		}
+	}
+	_, hasURL := columns["url"]
+	_, hasSlug := columns["slug"]
+	_, hasPattern := columns["pattern"]
+	_, hasTags := columns["tags"]
+	if (!hasURL && !hasSlug) || (!hasPattern && !hasTags) {
+		return nil, ErrInvalidImportFile
+	}
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, ErrInvalidImportFile
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, importRow{
+			Line:        line,
+			Title:       field("title"),
+			URL:         field("url"),
+			Slug:        field("slug"),
+			Pattern:     field("pattern"),
+			Tags:        splitTags(field("tags")),
+			Difficulty:  field("difficulty"),
+			Confidence:  field("confidence"),
+			InsightNote: field("insight_note"),
+			SolvedAt:    field("solved_at"),
+		})
+	}
+
+	return rows, nil
+}
+
+// parseJSONRows reads an array of problems. An element that does not decode
+// is reported on its own instead of failing the whole file.
+func parseJSONRows(body io.Reader) ([]importRow, error) {
+	var elements []json.RawMessage
+	if err := json.NewDecoder(body).Decode(&elements); err != nil {
+		return nil, ErrInvalidImportFile
+	}
+
+	rows := make([]importRow, len(elements))
+	for i, element := range elements {
+		var item jsonImportRow
+		if err := json.Unmarshal(element, &item); err != nil {
+			rows[i] = importRow{Line: i + 1, Reason: "item JSON inválido"}
+			continue
+		}
+
+		slug := item.Slug
+		if slug == "" {
+			slug = item.TitleSlug
+		}
+		solvedAt := item.SolvedAt
+		if solvedAt == "" {
+			solvedAt = jsonScalar(item.Timestamp)
+		}
+
+		rows[i] = importRow{
+			Line:        i + 1,
+			Title:       strings.TrimSpace(item.Title),
+			URL:         strings.TrimSpace(item.URL),
+			Slug:        strings.TrimSpace(slug),
+			Pattern:     strings.TrimSpace(item.Pattern),
+			Tags:        item.Tags,
+			Difficulty:  item.Difficulty,
+			Confidence:  jsonScalar(item.Confidence),
+			InsightNote: strings.TrimSpace(item.InsightNote),
+			SolvedAt:    strings.TrimSpace(solvedAt),
+		}
+	}
+
+	return rows, nil
+}
+
+func splitTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.Split(value, ";") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// jsonScalar returns a JSON number or string as text.
+func jsonScalar(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return strings.TrimSpace(s)
+	}
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return n.String()
+	}
+	return ""
+}
+
+// normalizeProblemURL reduces LeetCode URLs and slugs to ProblemURL, so the
+// same problem is recognised however it was written, and keeps other http
+// URLs as they are.
+func normalizeProblemURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if slug, err := ParseSlug(raw); err == nil {
+		return ProblemURL(slug), nil
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", errors.New("invalid url")
+	}
+	return raw, nil
+}
+
+// parseSolvedAt accepts a date, an RFC 3339 time or Unix seconds.
+func parseSolvedAt(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(seconds, 0).UTC(), nil
+}
+
+// confidenceIntervals is the first interval, in days, of a problem imported
+// with each confidence: the surer the user is, the later it comes back.
+var confidenceIntervals = [6]int{0, 1, 3, 7, 14, 30}
+
+// seedFromConfidence turns a confidence from 1 to 5 into the state of a
+// problem last reviewed at solvedAt with that score. The ease factor moves
+// 0.15 away from the default of 2.5 for each step away from 3.
+func seedFromConfidence(problem *LeetCodeProblem, confidence int, solvedAt time.Time) {
+	problem.LastScore = confidence
+	problem.EaseFactor = 2.5 + 0.15*float64(confidence-3)
+	problem.Interval = confidenceIntervals[confidence]
+	problem.LastReviewedAt = &solvedAt
+	problem.NextReview = solvedAt.AddDate(0, 0, problem.Interval)
+}
+
+// toImportedProblem validates a row and builds its problem. It returns a
+// non-empty reason when the row cannot be imported.
+func toImportedProblem(row importRow, userID uuid.UUID, opts BulkImportOptions, now time.Time) (*LeetCodeProblem, string) {
+	if row.Reason != "" {
+		return nil, row.Reason
+	}
+	if row.Title == "" && row.URL == "" && row.Slug == "" {
+		return nil, "linha sem dados do problema"
+	}
+
+	if n := utf8.RuneCountInString(row.Title); n < 3 || n > 200 {
+		return nil, "título deve ter entre 3 e 200 caracteres"
+	}
+
+	source := row.URL
+	if source == "" {
+		source = row.Slug
+	}
+	if source == "" {
+		return nil, "url ou slug é obrigatório"
+	}
+	problemURL, err := normalizeProblemURL(source)
+	if err != nil {
+		return nil, "url inválida: " + source
+	}
+
+	var names []string
+	if row.Pattern != "" {
+		pattern, ok := ParsePattern(row.Pattern)
+		if !ok {
+			return nil, "padrão inválido: " + row.Pattern
+		}
+		names = append(names, string(pattern))
+	}
+	names = append(names, row.Tags...)
+	if len(names) == 0 {
+		return nil, "padrão ou tags é obrigatório"
+	}
+	names, err = normalizeTagNames(names)
+	if err != nil {
+		return nil, "tags inválidas: use de 1 a 20 tags de até 50 caracteres"
+	}
+
+	difficulty, ok := ParseDifficulty(row.Difficulty)
+	if !ok {
+		return nil, "dificuldade inválida: " + row.Difficulty
+	}
+
+	confidence := opts.Confidence
+	if row.Confidence != "" {
+		confidence, err = strconv.Atoi(row.Confidence)
+		if err != nil || confidence < 1 || confidence > 5 {
+			return nil, "confiança deve ser um número de 1 a 5"
+		}
+	}
+
+	solvedAt := now
+	if row.SolvedAt != "" {
+		solvedAt, err = parseSolvedAt(row.SolvedAt)
+		if err != nil {
+			return nil, "solved_at inválido: " + row.SolvedAt
+		}
+		if solvedAt.After(now) {
+			return nil, "solved_at no futuro"
+		}
+	}
+
+	problem := &LeetCodeProblem{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Title:      row.Title,
+		URL:        problemURL,
+		Difficulty: difficulty,
+	}
+	// The tags are resolved when the problem is created; until then they
+	// only carry the names.
+	for _, name := range names {
+		problem.Tags = append(problem.Tags, Tag{Name: name})
+	}
+	if row.InsightNote != "" {
+		if utf8.RuneCountInString(row.InsightNote) > 2000 {
+			return nil, "nota deve ter no máximo 2000 caracteres"
+		}
+		note := row.InsightNote
+		problem.InsightNote = &note
+	}
+	seedFromConfidence(problem, confidence, solvedAt)
+
+	return problem, ""
+}
@@ -0,0 +1,210 @@
+package leetcode
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestParseCSVRows(t *testing.T) {
+	file := "\ufeffSlug,Title , Difficulty,Tags,Confidence,Insight Note,Extra\n" +
+		"two-sum,Two Sum,Easy,Arrays; Hash Table ;,4,use a map,x\n" +
+		"\n" +
+		",,,,,,\n" +
+		"\"lru-cache\",\"LRU Cache, again\",medium,Design,,,\n" +
+		"short-row,Short\n"
+
+	rows, err := parseCSVRows(strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("parseCSVRows: %v", err)
+	}
+
+	want := []importRow{
+		{Line: 2, Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", Tags: []string{"Arrays", "Hash Table"}, Confidence: "4", InsightNote: "use a map"},
+		{Line: 5, Slug: "lru-cache", Title: "LRU Cache, again", Difficulty: "medium", Tags: []string{"Design"}},
+		{Line: 6, Slug: "short-row", Title: "Short"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %+v, want %+v", rows, want)
+	}
+}
+
+func TestParseCSVRowsInvalidHeader(t *testing.T) {
+	files := []string{
+		"",
+		"title,url,pattern\n",
+		"title,difficulty,pattern\n",
+		"title,difficulty,url\n",
+		"url,difficulty,tags\n",
+		"title,difficulty,url,tags\n\"unterminated,Easy,x,y\n",
+	}
+
+	for _, file := range files {
+		if _, err := parseCSVRows(strings.NewReader(file)); err != ErrInvalidImportFile {
+			t.Errorf("parseCSVRows(%q) error = %v, want ErrInvalidImportFile", file, err)
+		}
+	}
+}
+
+func TestParseJSONRows(t *testing.T) {
+	file := `[
+		{"title": "Two Sum", "url": " https://leetcode.com/problems/two-sum/ ", "pattern": "Two Pointers", "difficulty": "Easy", "confidence": 5, "solved_at": "2026-01-10"},
+		{"title": "LRU Cache", "titleSlug": "lru-cache", "tags": ["Design"], "difficulty": "Medium", "confidence": "2", "timestamp": 1767225600},
+		{"title": 42},
+		"not an object"
+	]`
+
+	rows, err := parseJSONRows(strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("parseJSONRows: %v", err)
+	}
+
+	want := []importRow{
+		{Line: 1, Title: "Two Sum", URL: "https://leetcode.com/problems/two-sum/", Pattern: "Two Pointers", Difficulty: "Easy", Confidence: "5", SolvedAt: "2026-01-10"},
+		{Line: 2, Title: "LRU Cache", Slug: "lru-cache", Tags: []string{"Design"}, Difficulty: "Medium", Confidence: "2", SolvedAt: "1767225600"},
+		{Line: 3, Reason: "item JSON inválido"},
+		{Line: 4, Reason: "item JSON inválido"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %+v, want %+v", rows, want)
+	}
+
+	for _, file := range []string{"", `{"title": "Two Sum"}`, `[{"title": "Two Sum"}`} {
+		if _, err := parseJSONRows(strings.NewReader(file)); err != ErrInvalidImportFile {
+			t.Errorf("parseJSONRows(%q) error = %v, want ErrInvalidImportFile", file, err)
+		}
+	}
+}
+
+func TestParseImportRowsFormat(t *testing.T) {
+	if _, err := parseImportRows(strings.NewReader("[]"), ImportFormat("xml")); err != ErrInvalidImportFormat {
+		t.Errorf("error = %v, want ErrInvalidImportFormat", err)
+	}
+}
+
+func TestNormalizeProblemURL(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{"two-sum", "https://leetcode.com/problems/two-sum/", false},
+		{"https://leetcode.com/problems/two-sum", "https://leetcode.com/problems/two-sum/", false},
+		{"https://leetcode.com/problems/Two-Sum/description/?envType=daily", "https://leetcode.com/problems/two-sum/", false},
+		{" leetcode.com/problems/two-sum/solutions/ ", "https://leetcode.com/problems/two-sum/", false},
+		{"https://leetcode.cn/problems/two-sum/", "https://leetcode.com/problems/two-sum/", false},
+		{"https://neetcode.io/problems/two-integer-sum", "https://neetcode.io/problems/two-integer-sum", false},
+		{"ftp://leetcode.com/file", "", true},
+		{"not a url", "", true},
+		{"https://", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := normalizeProblemURL(tt.raw)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("normalizeProblemURL(%q) = %q, %v, want %q, error %v", tt.raw, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestSeedFromConfidence(t *testing.T) {
+	solvedAt := time.Date(2026, 2, 1, 18, 30, 0, 0, time.UTC)
+	tests := []struct {
+		confidence   int
+		wantEase     float64
+		wantInterval int
+	}{
+		{1, 2.2, 1},
+		{2, 2.35, 3},
+		{3, 2.5, 7},
+		{4, 2.65, 14},
+		{5, 2.8, 30},
+	}
+
+	for _, tt := range tests {
+		var problem LeetCodeProblem
+		seedFromConfidence(&problem, tt.confidence, solvedAt)
+
+		if problem.LastScore != tt.confidence || problem.Interval != tt.wantInterval || math.Abs(problem.EaseFactor-tt.wantEase) > 1e-9 {
+			t.Errorf("confidence %d: score, ease, interval = %d, %v, %d, want %d, %v, %d",
+				tt.confidence, problem.LastScore, problem.EaseFactor, problem.Interval, tt.confidence, tt.wantEase, tt.wantInterval)
+		}
+		if problem.LastReviewedAt == nil || !problem.LastReviewedAt.Equal(solvedAt) {
+			t.Errorf("confidence %d: last reviewed = %v, want %v", tt.confidence, problem.LastReviewedAt, solvedAt)
+		}
+		if want := solvedAt.AddDate(0, 0, tt.wantInterval); !problem.NextReview.Equal(want) {
+			t.Errorf("confidence %d: next review = %v, want %v", tt.confidence, problem.NextReview, want)
+		}
+	}
+}
+
+func TestToImportedProblem(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+	opts := BulkImportOptions{Confidence: defaultConfidence}
+
+	valid := importRow{Line: 2, Title: "Two Sum", Slug: "two-sum", Pattern: "two pointers", Tags: []string{"Arrays", "Two Pointers"}, Difficulty: "easy"}
+
+	problem, reason := toImportedProblem(valid, userID, opts, now)
+	if reason != "" {
+		t.Fatalf("reason = %q, want none", reason)
+	}
+	if problem.UserID != userID || problem.Title != "Two Sum" || problem.URL != "https://leetcode.com/problems/two-sum/" || problem.Difficulty != DifficultyEasy {
+		t.Errorf("problem = %+v", problem)
+	}
+	if got := tagNames(problem.Tags); !reflect.DeepEqual(got, []string{string(PatternTwoPointers), "Arrays"}) {
+		t.Errorf("tags = %v", got)
+	}
+	if problem.LastScore != defaultConfidence || !problem.LastReviewedAt.Equal(now) || problem.InsightNote != nil {
+		t.Errorf("seeded state = %d, %v, %v", problem.LastScore, problem.LastReviewedAt, problem.InsightNote)
+	}
+
+	withRow := func(change func(*importRow)) importRow {
+		row := valid
+		change(&row)
+		return row
+	}
+
+	seeded, reason := toImportedProblem(withRow(func(r *importRow) {
+		r.Confidence = "5"
+		r.SolvedAt = "2026-02-01"
+		r.InsightNote = "hash map of complements"
+	}), userID, opts, now)
+	if reason != "" {
+		t.Fatalf("reason = %q, want none", reason)
+	}
+	if seeded.LastScore != 5 || !seeded.NextReview.Equal(time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)) || *seeded.InsightNote != "hash map of complements" {
+		t.Errorf("seeded = %d, %v, %v", seeded.LastScore, seeded.NextReview, seeded.InsightNote)
+	}
+
+	skipped := []struct {
+		row  importRow
+		want string
+	}{
+		{importRow{Reason: "item JSON inválido"}, "item JSON inválido"},
+		{importRow{Difficulty: "Easy"}, "linha sem dados do problema"},
+		{withRow(func(r *importRow) { r.Title = "ab" }), "título deve ter entre 3 e 200 caracteres"},
+		{withRow(func(r *importRow) { r.Slug = "" }), "url ou slug é obrigatório"},
+		{withRow(func(r *importRow) { r.Slug = "not a url" }), "url inválida: not a url"},
+		{withRow(func(r *importRow) { r.Pattern = "recursion" }), "padrão inválido: recursion"},
+		{withRow(func(r *importRow) { r.Pattern, r.Tags = "", nil }), "padrão ou tags é obrigatório"},
+		{withRow(func(r *importRow) { r.Tags = []string{strings.Repeat("x", 51)} }), "tags inválidas: use de 1 a 20 tags de até 50 caracteres"},
+		{withRow(func(r *importRow) { r.Difficulty = "insane" }), "dificuldade inválida: insane"},
+		{withRow(func(r *importRow) { r.Confidence = "6" }), "confiança deve ser um número de 1 a 5"},
+		{withRow(func(r *importRow) { r.Confidence = "high" }), "confiança deve ser um número de 1 a 5"},
+		{withRow(func(r *importRow) { r.SolvedAt = "yesterday" }), "solved_at inválido: yesterday"},
+		{withRow(func(r *importRow) { r.SolvedAt = "2026-03-02" }), "solved_at no futuro"},
+		{withRow(func(r *importRow) { r.InsightNote = strings.Repeat("é", 2001) }), "nota deve ter no máximo 2000 caracteres"},
+	}
+
+	for _, tt := range skipped {
+		problem, reason := toImportedProblem(tt.row, userID, opts, now)
+		if problem != nil || reason != tt.want {
+			t.Errorf("toImportedProblem(%+v) = %v, %q, want nil, %q", tt.row, problem, reason, tt.want)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package leetcode
 
 import (
+	"time"
+
+	"github.com/saulo-duarte/chronos/internal/auth"
+	"github.com/saulo-duarte/chronos/internal/shared/config"
 	"gorm.io/gorm"
 )
 
@@ -10,9 +14,10 @@ type Container struct {
 	Handler    *Handler
 }
 
-func NewContainer(db *gorm.DB) *Container {
+func NewContainer(db *gorm.DB, cfg *config.Config, locator auth.Locator) *Container {
 	repo := NewRepository(db)
-	svc := NewService(repo)
+	importer := NewGraphQLImporter(cfg.LeetCodeGraphQLURL, nil)
+	svc := NewService(repo, importer, locator, time.Duration(cfg.ReviewUndoWindowMinutes)*time.Minute)
 	hdl := NewHandler(svc)
 
 	return &Container{
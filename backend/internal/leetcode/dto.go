@@ -9,36 +9,225 @@ import (
 type CreateProblemDTO struct {
 	Title       string     `json:"title" validate:"required,min=3,max=200"`
 	URL         string     `json:"url" validate:"required,url"`
-	Pattern     Pattern    `json:"pattern" validate:"required"`
+	Tags        []string   `json:"tags" validate:"required_without=Pattern,max=20"`
 	Difficulty  Difficulty `json:"difficulty" validate:"required"`
 	InsightNote *string    `json:"insight_note,omitempty" validate:"omitempty,max=2000"`
+
+	// Deprecated: Pattern is sent by clients that predate tags. It becomes
+	// the first tag.
+	Pattern *Pattern `json:"pattern,omitempty"`
 }
 
 type UpdateProblemDTO struct {
 	Title       *string     `json:"title" validate:"omitempty,min=3,max=200"`
 	URL         *string     `json:"url" validate:"omitempty,url"`
-	Pattern     *Pattern    `json:"pattern" validate:"omitempty"`
+	Tags        *[]string   `json:"tags" validate:"omitempty,min=1,max=20"`
 	Difficulty  *Difficulty `json:"difficulty" validate:"omitempty"`
 	InsightNote *string     `json:"insight_note" validate:"omitempty,max=2000"`
+
+	// Deprecated: Pattern is sent by clients that predate tags. It replaces
+	// the problem's pattern tags, keeping its own tags, or becomes the first
+	// of Tags when both are sent.
+	Pattern *Pattern `json:"pattern"`
+}
+
+// ImportProblemDTO adds a problem from its LeetCode URL or slug. Tags are
+// added to the patterns inferred from the topic tags.
+type ImportProblemDTO struct {
+	Source      string   `json:"source" validate:"required"`
+	Tags        []string `json:"tags,omitempty"`
+	InsightNote *string  `json:"insight_note,omitempty" validate:"omitempty,max=2000"`
+}
+
+// ProblemMetadataResponseDTO previews an import. Patterns is empty when no
+// topic tag maps to one.
+type ProblemMetadataResponseDTO struct {
+	Number     int        `json:"number"`
+	Title      string     `json:"title"`
+	Slug       string     `json:"slug"`
+	URL        string     `json:"url"`
+	Difficulty Difficulty `json:"difficulty"`
+	TopicTags  []string   `json:"topic_tags"`
+	Patterns   []Pattern  `json:"patterns"`
+}
+
+// BulkImportItemDTO reports one row of an import file; Line is the line in
+// a CSV file or the position in a JSON array.
+type BulkImportItemDTO struct {
+	Line       int          `json:"line"`
+	Title      string       `json:"title,omitempty"`
+	URL        string       `json:"url,omitempty"`
+	Tags       []string     `json:"tags,omitempty"`
+	Difficulty Difficulty   `json:"difficulty,omitempty"`
+	Confidence int          `json:"confidence,omitempty"`
+	NextReview *time.Time   `json:"next_review,omitempty"`
+	Action     ImportAction `json:"action"`
+	Reason     string       `json:"reason,omitempty"`
+	ProblemID  *uuid.UUID   `json:"problem_id,omitempty"`
+}
+
+type BulkImportResultDTO struct {
+	DryRun     bool                `json:"dry_run"`
+	Created    int                 `json:"created"`
+	Duplicates int                 `json:"duplicates"`
+	Skipped    int                 `json:"skipped"`
+	Items      []BulkImportItemDTO `json:"items"`
 }
 
 type ReviewDTO struct {
-	Score       int     `json:"score" validate:"required,min=1,max=5"`
-	InsightNote *string `json:"insight_note,omitempty" validate:"omitempty,max=2000"`
+	Score            int     `json:"score" validate:"required,min=1,max=5"`
+	InsightNote      *string `json:"insight_note,omitempty" validate:"omitempty,max=2000"`
+	TimeSpentSeconds *int    `json:"time_spent_seconds,omitempty" validate:"omitempty,min=0"`
 }
 
 type ProblemResponseDTO struct {
 	ID          uuid.UUID  `json:"id"`
 	Title       string     `json:"title"`
 	URL         string     `json:"url"`
-	Pattern     Pattern    `json:"pattern"`
+	Tags        []string   `json:"tags"`
 	Difficulty  Difficulty `json:"difficulty"`
 	LastScore   int        `json:"last_score"`
 	NextReview  time.Time  `json:"next_review"`
 	EaseFactor  float64    `json:"ease_factor"`
 	Interval    int        `json:"interval"`
 	InsightNote *string    `json:"insight_note,omitempty"`
+	Number      *int       `json:"number,omitempty"`
+	TopicTags   []string   `json:"topic_tags,omitempty"`
 	CreatedAt   time.Time  `json:"created_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+
+	LastReviewedAt *time.Time `json:"last_reviewed_at,omitempty"`
+	FSRS           *FSRSState `json:"fsrs,omitempty"`
+
+	// Deprecated: Pattern is the first pattern among Tags, kept for clients
+	// that predate tags.
+	Pattern Pattern `json:"pattern,omitempty"`
+}
+
+// ProblemFilter narrows problem listings to the problems that have every
+// one of Tags, matched ignoring case.
+type ProblemFilter struct {
+	Tags []string
+}
+
+// TagResponseDTO is a tag available to the user; Problems counts the user's
+// problems carrying it.
+type TagResponseDTO struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Canonical bool      `json:"canonical"`
+	Problems  int       `json:"problems"`
+}
+
+// ProblemStatsDTO aggregates a group of problems. AvgLastScore only counts
+// reviewed problems and LapseRate is the share of logged reviews scored
+// below 3; both are nil when there is nothing to average. DueNext7Days
+// includes overdue problems. Mastery goes from 0 to 100 and weighs the last
+// score, the length of the interval and the lapses of each problem, with
+// problems never reviewed counting as 0.
+type ProblemStatsDTO struct {
+	Problems      int      `json:"problems"`
+	Reviewed      int      `json:"reviewed"`
+	AvgLastScore  *float64 `json:"avg_last_score"`
+	AvgEaseFactor *float64 `json:"avg_ease_factor"`
+	LapseRate     *float64 `json:"lapse_rate"`
+	DueNext7Days  int      `json:"due_next_7_days"`
+	Mastery       int      `json:"mastery"`
+}
+
+type PatternStatsDTO struct {
+	Pattern Pattern `json:"pattern"`
+	ProblemStatsDTO
+}
+
+type DifficultyStatsDTO struct {
+	Difficulty Difficulty `json:"difficulty"`
+	ProblemStatsDTO
+}
+
+// StatsResponseDTO lists every pattern, weakest first, followed by the ones
+// without problems.
+type StatsResponseDTO struct {
+	Overall      ProblemStatsDTO      `json:"overall"`
+	ByPattern    []PatternStatsDTO    `json:"by_pattern"`
+	ByDifficulty []DifficultyStatsDTO `json:"by_difficulty"`
+}
+
+type UpdateReviewSettingsDTO struct {
+	Algorithm        *Algorithm `json:"algorithm,omitempty"`
+	DesiredRetention *float64   `json:"desired_retention,omitempty" validate:"omitempty,min=0.7,max=0.97"`
+	MaxReviewsPerDay *int       `json:"max_reviews_per_day,omitempty" validate:"omitempty,min=1,max=500"`
+	MaxNewPerDay     *int       `json:"max_new_per_day,omitempty" validate:"omitempty,min=0,max=100"`
+}
+
+type ReviewSettingsResponseDTO struct {
+	Algorithm        Algorithm `json:"algorithm"`
+	DesiredRetention float64   `json:"desired_retention"`
+	MaxReviewsPerDay int       `json:"max_reviews_per_day"`
+	MaxNewPerDay     int       `json:"max_new_per_day"`
+}
+
+type SessionItemDTO struct {
+	Problem ProblemResponseDTO `json:"problem"`
+	IsNew   bool               `json:"is_new"`
+	Done    bool               `json:"done"`
+}
+
+// ReviewSessionResponseDTO is the daily queue. Backlog counts the due
+// reviews left out by the daily limit and BacklogDays estimates how many
+// days it takes to clear them at that limit.
+type ReviewSessionResponseDTO struct {
+	Day         string           `json:"day"`
+	Total       int              `json:"total"`
+	Completed   int              `json:"completed"`
+	Remaining   int              `json:"remaining"`
+	Backlog     int              `json:"backlog"`
+	BacklogDays int              `json:"backlog_days"`
+	Next        *SessionItemDTO  `json:"next,omitempty"`
+	Items       []SessionItemDTO `json:"items"`
+}
+
+type ReviewLogResponseDTO struct {
+	ID               uuid.UUID `json:"id"`
+	Algorithm        Algorithm `json:"algorithm"`
+	Score            int       `json:"score"`
+	PrevInterval     int       `json:"prev_interval"`
+	NewInterval      int       `json:"new_interval"`
+	PrevEaseFactor   float64   `json:"prev_ease_factor"`
+	NewEaseFactor    float64   `json:"new_ease_factor"`
+	NextReview       time.Time `json:"next_review"`
+	TimeSpentSeconds *int      `json:"time_spent_seconds,omitempty"`
+	Note             *string   `json:"note,omitempty"`
+	ReviewedAt       time.Time `json:"reviewed_at"`
+}
+
+// RetentionPointDTO is an observed recall: how many days had passed since
+// the previous review and whether the problem was still remembered.
+type RetentionPointDTO struct {
+	ReviewedAt  time.Time `json:"reviewed_at"`
+	ElapsedDays float64   `json:"elapsed_days"`
+	Score       int       `json:"score"`
+	Recalled    bool      `json:"recalled"`
+}
+
+type RetentionSampleDTO struct {
+	Day       int     `json:"day"`
+	Retention float64 `json:"retention"`
+}
+
+// RetentionCurveDTO is the forgetting curve R(t) = exp(-t/S) fitted to the
+// observed recalls of a problem. Stability is nil until there is at least
+// one review following another.
+type RetentionCurveDTO struct {
+	Stability *float64             `json:"stability_days"`
+	Points    []RetentionPointDTO  `json:"points"`
+	Curve     []RetentionSampleDTO `json:"curve"`
+}
+
+type ReviewHistoryResponseDTO struct {
+	ProblemID uuid.UUID              `json:"problem_id"`
+	Reviews   []ReviewLogResponseDTO `json:"reviews"`
+	Retention RetentionCurveDTO      `json:"retention"`
 }
 
 func (dto *CreateProblemDTO) ToEntity(userID uuid.UUID) *LeetCodeProblem {
@@ -47,7 +236,6 @@ func (dto *CreateProblemDTO) ToEntity(userID uuid.UUID) *LeetCodeProblem {
 		UserID:      userID,
 		Title:       dto.Title,
 		URL:         dto.URL,
-		Pattern:     dto.Pattern,
 		Difficulty:  dto.Difficulty,
 		InsightNote: dto.InsightNote,
 		NextReview:  time.Now(),
@@ -58,18 +246,30 @@ func (dto *CreateProblemDTO) ToEntity(userID uuid.UUID) *LeetCodeProblem {
 }
 
 func ToResponse(p LeetCodeProblem) ProblemResponseDTO {
+	var deletedAt *time.Time
+	if p.DeletedAt.Valid {
+		deletedAt = &p.DeletedAt.Time
+	}
+
 	return ProblemResponseDTO{
 		ID:          p.ID,
 		Title:       p.Title,
 		URL:         p.URL,
-		Pattern:     p.Pattern,
+		Tags:        tagNames(p.Tags),
+		Pattern:     firstPattern(p.Tags),
 		Difficulty:  p.Difficulty,
 		LastScore:   p.LastScore,
 		NextReview:  p.NextReview,
 		EaseFactor:  p.EaseFactor,
 		Interval:    p.Interval,
 		InsightNote: p.InsightNote,
+		Number:      p.Number,
+		TopicTags:   p.TopicTags,
 		CreatedAt:   p.CreatedAt,
+		DeletedAt:   deletedAt,
+
+		LastReviewedAt: p.LastReviewedAt,
+		FSRS:           p.SchedulerState.FSRS,
 	}
 }
 
@@ -80,3 +280,134 @@ func ToResponseList(problems []LeetCodeProblem) []ProblemResponseDTO {
 	}
 	return responses
 }
+
+func ToReviewLogResponse(l ReviewLog) ReviewLogResponseDTO {
+	return ReviewLogResponseDTO{
+		ID:               l.ID,
+		Algorithm:        l.Algorithm,
+		Score:            l.Score,
+		PrevInterval:     l.PrevInterval,
+		NewInterval:      l.NewInterval,
+		PrevEaseFactor:   l.PrevEaseFactor,
+		NewEaseFactor:    l.NewEaseFactor,
+		NextReview:       l.NewNextReview,
+		TimeSpentSeconds: l.TimeSpentSeconds,
+		Note:             l.Note,
+		ReviewedAt:       l.ReviewedAt,
+	}
+}
+
+func ToReviewLogResponseList(logs []ReviewLog) []ReviewLogResponseDTO {
+	responses := make([]ReviewLogResponseDTO, len(logs))
+	for i, l := range logs {
+		responses[i] = ToReviewLogResponse(l)
+	}
+	return responses
+}
+
+func ToReviewSettingsResponse(s *ReviewSettings) ReviewSettingsResponseDTO {
+	return ReviewSettingsResponseDTO{
+		Algorithm:        s.Algorithm,
+		DesiredRetention: s.DesiredRetention,
+		MaxReviewsPerDay: s.MaxReviewsPerDay,
+		MaxNewPerDay:     s.MaxNewPerDay,
+	}
+}
+
+// toSessionResponse lists the session problems in queue order, skipping
+// the ones deleted since the session was built.
+func toSessionResponse(session *ReviewSession, problems []LeetCodeProblem, maxReviewsPerDay int) *ReviewSessionResponseDTO {
+	byID := make(map[uuid.UUID]LeetCodeProblem, len(problems))
+	for _, p := range problems {
+		byID[p.ID] = p
+	}
+
+	response := &ReviewSessionResponseDTO{
+		Day:     session.Day,
+		Backlog: session.Backlog,
+		Items:   []SessionItemDTO{},
+	}
+	if maxReviewsPerDay > 0 {
+		response.BacklogDays = (session.Backlog + maxReviewsPerDay - 1) / maxReviewsPerDay
+	}
+
+	for _, id := range session.ProblemIDs {
+		problem, ok := byID[id]
+		if !ok {
+			continue
+		}
+
+		item := SessionItemDTO{
+			Problem: ToResponse(problem),
+			IsNew:   containsID(session.NewIDs, id),
+			Done:    containsID(session.CompletedIDs, id),
+		}
+		response.Items = append(response.Items, item)
+
+		if item.Done {
+			response.Completed++
+		} else if response.Next == nil {
+			next := item
+			response.Next = &next
+		}
+	}
+
+	response.Total = len(response.Items)
+	response.Remaining = response.Total - response.Completed
+
+	return response
+}
+
+func ToMetadataResponse(m ProblemMetadata) ProblemMetadataResponseDTO {
+	return ProblemMetadataResponseDTO{
+		Number:     m.Number,
+		Title:      m.Title,
+		Slug:       m.Slug,
+		URL:        m.URL,
+		Difficulty: m.Difficulty,
+		TopicTags:  m.TopicTags,
+		Patterns:   m.Patterns,
+	}
+}
+
+func toProblemStats(row StatsRow) ProblemStatsDTO {
+	stats := ProblemStatsDTO{
+		Problems:      row.Problems,
+		Reviewed:      row.Reviewed,
+		AvgLastScore:  row.AvgLastScore,
+		AvgEaseFactor: row.AvgEaseFactor,
+		LapseRate:     row.LapseRate,
+		DueNext7Days:  row.DueSoon,
+	}
+	if row.Mastery != nil {
+		stats.Mastery = int(*row.Mastery)
+	}
+	return stats
+}
+
+func ToStatsResponse(patterns, difficulties []StatsRow) StatsResponseDTO {
+	response := StatsResponseDTO{
+		ByPattern:    make([]PatternStatsDTO, 0, len(patterns)),
+		ByDifficulty: make([]DifficultyStatsDTO, 0, len(difficulties)),
+	}
+
+	for _, row := range patterns {
+		response.ByPattern = append(response.ByPattern, PatternStatsDTO{
+			Pattern:         Pattern(row.Key),
+			ProblemStatsDTO: toProblemStats(row),
+		})
+	}
+
+	for _, row := range difficulties {
+		if row.Overall {
+			response.Overall = toProblemStats(row)
+			continue
+		}
+		response.ByDifficulty = append(response.ByDifficulty, DifficultyStatsDTO{
+			Difficulty:      Difficulty(row.Key),
+			ProblemStatsDTO: toProblemStats(row),
+		})
+	}
+
+	return response
+}
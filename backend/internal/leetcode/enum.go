@@ -1,5 +1,7 @@
 package leetcode
 
+import "strings"
+
 type Pattern string
 
 const (
@@ -27,6 +29,29 @@ const (
 	PatternBitManipulation  Pattern = "Bit Manipulation"
 )
 
+// Patterns lists every pattern in display order.
+var Patterns = []Pattern{
+	PatternSlidingWindow, PatternTwoPointers, PatternFastSlowPointers,
+	PatternMergeIntervals, PatternCyclicSort, PatternInPlaceReversal,
+	PatternBFS, PatternDFS, PatternTwoHeaps, PatternSubsets,
+	PatternBinarySearch, PatternTopKElements, PatternKWayMerge,
+	PatternBacktracking, PatternDP, PatternGreedy, PatternGraphs,
+	PatternTrie, PatternTopologicalSort, PatternUnionFind,
+	PatternMonotonicStack, PatternBitManipulation,
+}
+
+// ParsePattern matches s against the pattern names ignoring case and
+// surrounding spaces, as typed in spreadsheets.
+func ParsePattern(s string) (Pattern, bool) {
+	s = strings.TrimSpace(s)
+	for _, p := range Patterns {
+		if strings.EqualFold(string(p), s) {
+			return p, true
+		}
+	}
+	return "", false
+}
+
 func (p Pattern) IsValid() bool {
 	switch p {
 	case PatternSlidingWindow, PatternTwoPointers, PatternFastSlowPointers,
@@ -49,6 +74,17 @@ const (
 	DifficultyHard   Difficulty = "Hard"
 )
 
+// ParseDifficulty matches s against the difficulties ignoring case.
+func ParseDifficulty(s string) (Difficulty, bool) {
+	s = strings.TrimSpace(s)
+	for _, d := range []Difficulty{DifficultyEasy, DifficultyMedium, DifficultyHard} {
+		if strings.EqualFold(string(d), s) {
+			return d, true
+		}
+	}
+	return "", false
+}
+
 func (d Difficulty) IsValid() bool {
 	switch d {
 	case DifficultyEasy, DifficultyMedium, DifficultyHard:
@@ -56,3 +92,20 @@ func (d Difficulty) IsValid() bool {
 	}
 	return false
 }
+
+// Algorithm is the spaced repetition algorithm that schedules a user's
+// reviews.
+type Algorithm string
+
+const (
+	AlgorithmSM2  Algorithm = "SM2"
+	AlgorithmFSRS Algorithm = "FSRS"
+)
+
+func (a Algorithm) IsValid() bool {
+	switch a {
+	case AlgorithmSM2, AlgorithmFSRS:
+		return true
+	}
+	return false
+}
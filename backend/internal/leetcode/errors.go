@@ -3,9 +3,22 @@ package leetcode
 import "errors"
 
 var (
-	ErrProblemNotFound    = errors.New("leetcode problem not found")
-	ErrInvalidScore       = errors.New("score must be between 1 and 5")
-	ErrInvalidPattern     = errors.New("invalid pattern")
-	ErrInvalidDifficulty  = errors.New("invalid difficulty")
-	ErrUnauthorizedAccess = errors.New("unauthorized access to problem")
+	ErrProblemNotFound       = errors.New("leetcode problem not found")
+	ErrInvalidScore          = errors.New("score must be between 1 and 5")
+	ErrInvalidPattern        = errors.New("invalid pattern")
+	ErrInvalidTags           = errors.New("a problem needs 1 to 20 tags of up to 50 characters")
+	ErrInvalidDifficulty     = errors.New("invalid difficulty")
+	ErrUnauthorizedAccess    = errors.New("unauthorized access to problem")
+	ErrInvalidTimeSpent      = errors.New("time spent must not be negative")
+	ErrInvalidReviewSettings = errors.New("invalid review settings: algorithm must be SM2 or FSRS, desired retention between 0.7 and 0.97, 1 to 500 reviews and 0 to 100 new problems per day")
+	ErrNoReviewToUndo        = errors.New("problem has no review to undo")
+	ErrUndoWindowExpired     = errors.New("the last review can no longer be undone")
+	ErrInvalidProblemSource  = errors.New("expected a LeetCode problem URL or slug")
+	ErrProblemNotOnLeetCode  = errors.New("problem not found on LeetCode")
+	ErrLeetCodeUnavailable   = errors.New("could not reach LeetCode")
+	ErrPatternNotInferred    = errors.New("no pattern matches the problem's topic tags, add a tag")
+	ErrProblemAlreadyAdded   = errors.New("problem already added")
+	ErrInvalidImportFormat   = errors.New("import format must be csv or json")
+	ErrInvalidImportFile     = errors.New("invalid import file: expected a CSV with title, url or slug, pattern or tags and difficulty columns or a JSON array")
+	ErrInvalidConfidence     = errors.New("confidence must be between 1 and 5")
 )
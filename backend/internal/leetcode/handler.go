@@ -2,7 +2,11 @@ package leetcode
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -33,8 +37,102 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusCreated, problem)
 }
 
+func (h *Handler) Lookup(w http.ResponseWriter, r *http.Request) {
+	metadata, err := h.service.Lookup(r.Context(), r.URL.Query().Get("source"))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, metadata)
+}
+
+func (h *Handler) Import(w http.ResponseWriter, r *http.Request) {
+	var dto ImportProblemDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_PAYLOAD", "Payload inválido")
+		return
+	}
+
+	problem, err := h.service.Import(r.Context(), &dto)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, problem)
+}
+
+const maxImportSize = 5 << 20 // 5MB
+
+// BulkImport accepts a CSV or JSON file either as the "file" field of a
+// multipart form or as the raw request body. The format comes from the
+// format query parameter, the file extension or the Content-Type. Query
+// parameters: format, dry_run, confidence (default for rows without one).
+func (h *Handler) BulkImport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	opts := BulkImportOptions{
+		Format: ImportFormat(strings.ToLower(query.Get("format"))),
+		DryRun: query.Get("dry_run") == "true",
+	}
+
+	if value := query.Get("confidence"); value != "" {
+		confidence, err := strconv.Atoi(value)
+		if err != nil {
+			h.handleError(w, ErrInvalidConfidence)
+			return
+		}
+		opts.Confidence = confidence
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	var body io.Reader = r.Body
+	contentType := r.Header.Get("Content-Type")
+	if strings.HasPrefix(contentType, "multipart/form-data") {
+		if err := r.ParseMultipartForm(maxImportSize); err != nil {
+			response.Error(w, http.StatusBadRequest, "INVALID_PAYLOAD", "Erro ao processar formulário")
+			return
+		}
+
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "FILE_REQUIRED", "Arquivo .csv ou .json é obrigatório")
+			return
+		}
+		defer file.Close()
+		body = file
+
+		if opts.Format == "" {
+			opts.Format = ImportFormat(strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), "."))
+		}
+	}
+
+	if opts.Format == "" {
+		switch {
+		case strings.Contains(contentType, "csv"):
+			opts.Format = ImportCSV
+		case strings.Contains(contentType, "json"):
+			opts.Format = ImportJSON
+		}
+	}
+
+	result, err := h.service.BulkImport(r.Context(), body, opts)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if opts.DryRun {
+		status = http.StatusOK
+	}
+	response.JSON(w, status, result)
+}
+
 func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
-	problems, err := h.service.GetAll(r.Context())
+	problems, err := h.service.GetAll(r.Context(), parseProblemFilter(r))
 	if err != nil {
 		h.handleError(w, err)
 		return
@@ -44,7 +142,7 @@ func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) GetDue(w http.ResponseWriter, r *http.Request) {
-	problems, err := h.service.GetDueProblems(r.Context())
+	problems, err := h.service.GetDueProblems(r.Context(), parseProblemFilter(r))
 	if err != nil {
 		h.handleError(w, err)
 		return
@@ -113,6 +211,38 @@ func (h *Handler) Review(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusOK, problem)
 }
 
+func (h *Handler) UndoReview(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	problem, err := h.service.UndoReview(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, problem)
+}
+
+func (h *Handler) GetReviews(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	history, err := h.service.GetReviews(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, history)
+}
+
 func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
@@ -128,6 +258,88 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusOK, map[string]string{"message": "Problema excluído com sucesso"})
 }
 
+func (h *Handler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.service.GetSettings(r.Context())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, settings)
+}
+
+func (h *Handler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var dto UpdateReviewSettingsDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_PAYLOAD", "Payload inválido")
+		return
+	}
+
+	settings, err := h.service.UpdateSettings(r.Context(), &dto)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, settings)
+}
+
+func (h *Handler) GetSession(w http.ResponseWriter, r *http.Request) {
+	session, err := h.service.GetSession(r.Context())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, session)
+}
+
+func (h *Handler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	problems, err := h.service.GetTrash(r.Context())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, problems)
+}
+
+func (h *Handler) Restore(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	problem, err := h.service.Restore(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, problem)
+}
+
+func (h *Handler) GetTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.service.GetTags(r.Context())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, tags)
+}
+
+func (h *Handler) GetStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.service.GetStats(r.Context())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, stats)
+}
+
 func (h *Handler) handleError(w http.ResponseWriter, err error) {
 	switch err {
 	case ErrProblemNotFound:
@@ -136,6 +348,32 @@ func (h *Handler) handleError(w http.ResponseWriter, err error) {
 		response.Error(w, http.StatusBadRequest, "INVALID_SCORE", err.Error())
 	case ErrInvalidPattern:
 		response.Error(w, http.StatusBadRequest, "INVALID_PATTERN", err.Error())
+	case ErrInvalidReviewSettings:
+		response.Error(w, http.StatusBadRequest, "INVALID_SETTINGS", err.Error())
+	case ErrNoReviewToUndo:
+		response.Error(w, http.StatusNotFound, "NO_REVIEW_TO_UNDO", err.Error())
+	case ErrUndoWindowExpired:
+		response.Error(w, http.StatusConflict, "UNDO_WINDOW_EXPIRED", err.Error())
+	case ErrInvalidTimeSpent:
+		response.Error(w, http.StatusBadRequest, "INVALID_TIME_SPENT", err.Error())
+	case ErrInvalidProblemSource:
+		response.Error(w, http.StatusBadRequest, "INVALID_SOURCE", err.Error())
+	case ErrProblemNotOnLeetCode:
+		response.Error(w, http.StatusNotFound, "LEETCODE_PROBLEM_NOT_FOUND", err.Error())
+	case ErrLeetCodeUnavailable:
+		response.Error(w, http.StatusBadGateway, "LEETCODE_UNAVAILABLE", err.Error())
+	case ErrPatternNotInferred:
+		response.Error(w, http.StatusUnprocessableEntity, "PATTERN_REQUIRED", err.Error())
+	case ErrProblemAlreadyAdded:
+		response.Error(w, http.StatusConflict, "PROBLEM_ALREADY_ADDED", err.Error())
+	case ErrInvalidImportFormat:
+		response.Error(w, http.StatusBadRequest, "INVALID_FORMAT", err.Error())
+	case ErrInvalidImportFile:
+		response.Error(w, http.StatusBadRequest, "INVALID_FILE", err.Error())
+	case ErrInvalidConfidence:
+		response.Error(w, http.StatusBadRequest, "INVALID_CONFIDENCE", err.Error())
+	case ErrInvalidTags:
+		response.Error(w, http.StatusBadRequest, "INVALID_TAGS", err.Error())
 	case ErrInvalidDifficulty:
 		response.Error(w, http.StatusBadRequest, "INVALID_DIFFICULTY", err.Error())
 	case ErrUnauthorizedAccess:
@@ -144,3 +382,15 @@ func (h *Handler) handleError(w http.ResponseWriter, err error) {
 		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Erro interno no servidor")
 	}
 }
+
+// parseProblemFilter reads the repeatable tag query parameter of the problem
+// listings.
+func parseProblemFilter(r *http.Request) ProblemFilter {
+	var filter ProblemFilter
+	for _, tag := range r.URL.Query()["tag"] {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			filter.Tags = append(filter.Tags, tag)
+		}
+	}
+	return filter
+}
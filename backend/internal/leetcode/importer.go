@@ -0,0 +1,228 @@
+package leetcode
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProblemMetadata is what LeetCode knows about a problem. Patterns are the
+// ones its topic tags map to, most specific first.
+type ProblemMetadata struct {
+	Number     int
+	Title      string
+	Slug       string
+	URL        string
+	Difficulty Difficulty
+	TopicTags  []string
+	Patterns   []Pattern
+}
+
+// Importer fetches the metadata of a LeetCode problem by its slug.
+type Importer interface {
+	Fetch(ctx context.Context, slug string) (*ProblemMetadata, error)
+}
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// ParseSlug extracts the problem slug from a LeetCode URL such as
+// https://leetcode.com/problems/two-sum/description/ or accepts a bare slug.
+func ParseSlug(source string) (string, error) {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return "", ErrInvalidProblemSource
+	}
+
+	if strings.Contains(source, "/") {
+		if !strings.Contains(source, "://") {
+			source = "https://" + source
+		}
+		u, err := url.Parse(source)
+		if err != nil || !isLeetCodeHost(u.Hostname()) {
+			return "", ErrInvalidProblemSource
+		}
+
+		parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if len(parts) < 2 || parts[0] != "problems" {
+			return "", ErrInvalidProblemSource
+		}
+		source = parts[1]
+	}
+
+	slug := strings.ToLower(source)
+	if !slugPattern.MatchString(slug) {
+		return "", ErrInvalidProblemSource
+	}
+	return slug, nil
+}
+
+func isLeetCodeHost(host string) bool {
+	host = strings.ToLower(host)
+	return host == "leetcode.com" || strings.HasSuffix(host, ".leetcode.com") ||
+		host == "leetcode.cn" || strings.HasSuffix(host, ".leetcode.cn")
+}
+
+// ProblemURL is the canonical URL of a problem, used to recognise problems
+// that were already added.
+func ProblemURL(slug string) string {
+	return "https://leetcode.com/problems/" + slug + "/"
+}
+
+// topicPatterns maps LeetCode topic tag slugs to patterns, the most specific
+// technique first and generic tags such as "graph" or "greedy" last.
+var topicPatterns = []struct {
+	tag     string
+	pattern Pattern
+}{
+	{"topological-sort", PatternTopologicalSort},
+	{"union-find", PatternUnionFind},
+	{"trie", PatternTrie},
+	{"monotonic-stack", PatternMonotonicStack},
+	{"monotonic-queue", PatternMonotonicStack},
+	{"sliding-window", PatternSlidingWindow},
+	{"backtracking", PatternBacktracking},
+	{"bitmask", PatternBitManipulation},
+	{"bit-manipulation", PatternBitManipulation},
+	{"dynamic-programming", PatternDP},
+	{"memoization", PatternDP},
+	{"merge-sort", PatternKWayMerge},
+	{"heap-priority-queue", PatternTopKElements},
+	{"quickselect", PatternTopKElements},
+	{"binary-search", PatternBinarySearch},
+	{"two-pointers", PatternTwoPointers},
+	{"breadth-first-search", PatternBFS},
+	{"depth-first-search", PatternDFS},
+	{"graph", PatternGraphs},
+	{"shortest-path", PatternGraphs},
+	{"greedy", PatternGreedy},
+}
+
+// PatternsForTags returns the patterns of the given topic tags, most
+// specific first.
+func PatternsForTags(tags []string) []Pattern {
+	present := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		present[tag] = true
+	}
+
+	patterns := []Pattern{}
+	added := make(map[Pattern]bool)
+	for _, tp := range topicPatterns {
+		if present[tp.tag] && !added[tp.pattern] {
+			added[tp.pattern] = true
+			patterns = append(patterns, tp.pattern)
+		}
+	}
+	return patterns
+}
+
+const questionQuery = `query question($titleSlug: String!) {
+  question(titleSlug: $titleSlug) {
+    questionFrontendId
+    title
+    titleSlug
+    difficulty
+    topicTags { name slug }
+  }
+}`
+
+type graphQLImporter struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewGraphQLImporter returns an Importer backed by LeetCode's public
+// GraphQL API at endpoint.
+func NewGraphQLImporter(endpoint string, client *http.Client) Importer {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &graphQLImporter{endpoint: endpoint, client: client}
+}
+
+type questionResponse struct {
+	Data struct {
+		Question *struct {
+			QuestionFrontendID string `json:"questionFrontendId"`
+			Title              string `json:"title"`
+			TitleSlug          string `json:"titleSlug"`
+			Difficulty         string `json:"difficulty"`
+			TopicTags          []struct {
+				Name string `json:"name"`
+				Slug string `json:"slug"`
+			} `json:"topicTags"`
+		} `json:"question"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func (i *graphQLImporter) Fetch(ctx context.Context, slug string) (*ProblemMetadata, error) {
+	body, err := json.Marshal(map[string]any{
+		"query":     questionQuery,
+		"variables": map[string]string{"titleSlug": slug},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, i.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Referer", ProblemURL(slug))
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrLeetCodeUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", ErrLeetCodeUnavailable, resp.StatusCode)
+	}
+
+	var result questionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrLeetCodeUnavailable, err)
+	}
+
+	question := result.Data.Question
+	if question == nil {
+		if len(result.Errors) > 0 && !strings.Contains(strings.ToLower(result.Errors[0].Message), "not exist") {
+			return nil, fmt.Errorf("%w: %s", ErrLeetCodeUnavailable, result.Errors[0].Message)
+		}
+		return nil, ErrProblemNotOnLeetCode
+	}
+
+	difficulty := Difficulty(question.Difficulty)
+	if !difficulty.IsValid() {
+		return nil, fmt.Errorf("%w: unknown difficulty %q", ErrLeetCodeUnavailable, question.Difficulty)
+	}
+
+	tags := make([]string, len(question.TopicTags))
+	for i, tag := range question.TopicTags {
+		tags[i] = tag.Slug
+	}
+
+	number, _ := strconv.Atoi(question.QuestionFrontendID)
+
+	return &ProblemMetadata{
+		Number:     number,
+		Title:      question.Title,
+		Slug:       question.TitleSlug,
+		URL:        ProblemURL(question.TitleSlug),
+		Difficulty: difficulty,
+		TopicTags:  tags,
+		Patterns:   PatternsForTags(tags),
+	}, nil
+}
@@ -0,0 +1,148 @@
+package leetcode
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestParseSlug(t *testing.T) {
+	tests := []struct {
+		source  string
+		want    string
+		wantErr bool
+	}{
+		{"two-sum", "two-sum", false},
+		{"  Two-Sum ", "two-sum", false},
+		{"https://leetcode.com/problems/two-sum/", "two-sum", false},
+		{"https://leetcode.com/problems/two-sum/description/?envType=study-plan", "two-sum", false},
+		{"leetcode.com/problems/lru-cache", "lru-cache", false},
+		{"https://leetcode.cn/problems/two-sum/", "two-sum", false},
+		{"https://example.com/problems/two-sum/", "", true},
+		{"https://leetcode.com/explore/", "", true},
+		{"two sum", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSlug(tt.source)
+		if tt.wantErr {
+			if err != ErrInvalidProblemSource {
+				t.Errorf("ParseSlug(%q) error = %v, want ErrInvalidProblemSource", tt.source, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseSlug(%q) = %q, %v, want %q", tt.source, got, err, tt.want)
+		}
+	}
+}
+
+func TestPatternsForTags(t *testing.T) {
+	tests := []struct {
+		tags []string
+		want []Pattern
+	}{
+		{[]string{"array", "hash-table"}, []Pattern{}},
+		{[]string{"array", "two-pointers", "sorting"}, []Pattern{PatternTwoPointers}},
+		{[]string{"graph", "depth-first-search", "breadth-first-search", "topological-sort"}, []Pattern{PatternTopologicalSort, PatternBFS, PatternDFS, PatternGraphs}},
+		{[]string{"array", "binary-search", "sliding-window"}, []Pattern{PatternSlidingWindow, PatternBinarySearch}},
+		{[]string{"bitmask", "dynamic-programming", "bit-manipulation"}, []Pattern{PatternBitManipulation, PatternDP}},
+	}
+
+	for _, tt := range tests {
+		if got := PatternsForTags(tt.tags); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("PatternsForTags(%v) = %v, want %v", tt.tags, got, tt.want)
+		}
+	}
+}
+
+// stubLeetCode serves the GraphQL endpoint with the given questions, keyed
+// by slug; unknown slugs get the response LeetCode gives for them.
+func stubLeetCode(t *testing.T, questions map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+
+		var req struct {
+			Query     string            `json:"query"`
+			Variables map[string]string `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		question, ok := questions[req.Variables["titleSlug"]]
+		if !ok {
+			w.Write([]byte(`{"data":{"question":null}}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"question":` + question + `}}`))
+	}))
+}
+
+func TestGraphQLImporterFetch(t *testing.T) {
+	server := stubLeetCode(t, map[string]string{
+		"container-with-most-water": `{
+			"questionFrontendId": "11",
+			"title": "Container With Most Water",
+			"titleSlug": "container-with-most-water",
+			"difficulty": "Medium",
+			"topicTags": [
+				{"name": "Array", "slug": "array"},
+				{"name": "Two Pointers", "slug": "two-pointers"},
+				{"name": "Greedy", "slug": "greedy"}
+			]
+		}`,
+	})
+	defer server.Close()
+
+	importer := NewGraphQLImporter(server.URL, server.Client())
+
+	got, err := importer.Fetch(context.Background(), "container-with-most-water")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	want := &ProblemMetadata{
+		Number:     11,
+		Title:      "Container With Most Water",
+		Slug:       "container-with-most-water",
+		URL:        "https://leetcode.com/problems/container-with-most-water/",
+		Difficulty: DifficultyMedium,
+		TopicTags:  []string{"array", "two-pointers", "greedy"},
+		Patterns:   []Pattern{PatternTwoPointers, PatternGreedy},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Fetch = %+v, want %+v", got, want)
+	}
+}
+
+func TestGraphQLImporterNotFound(t *testing.T) {
+	server := stubLeetCode(t, nil)
+	defer server.Close()
+
+	_, err := NewGraphQLImporter(server.URL, server.Client()).Fetch(context.Background(), "no-such-problem")
+	if err != ErrProblemNotOnLeetCode {
+		t.Errorf("error = %v, want ErrProblemNotOnLeetCode", err)
+	}
+}
+
+func TestGraphQLImporterUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	_, err := NewGraphQLImporter(server.URL, server.Client()).Fetch(context.Background(), "two-sum")
+	if !errors.Is(err, ErrLeetCodeUnavailable) {
+		t.Errorf("error = %v, want ErrLeetCodeUnavailable", err)
+	}
+}
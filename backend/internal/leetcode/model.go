@@ -12,17 +12,26 @@ type LeetCodeProblem struct {
 	UserID      uuid.UUID  `json:"user_id" gorm:"type:uuid;index;not null"`
 	Title       string     `json:"title" gorm:"not null"`
 	URL         string     `json:"url" gorm:"not null"`
-	Pattern     Pattern    `json:"pattern" gorm:"not null"`
 	Difficulty  Difficulty `json:"difficulty" gorm:"not null"`
 	LastScore   int        `json:"last_score" gorm:"default:0"`
 	NextReview  time.Time  `json:"next_review" gorm:"index;not null"`
 	EaseFactor  float64    `json:"ease_factor" gorm:"default:2.5"`
 	Interval    int        `json:"interval" gorm:"default:1"`
 	InsightNote *string    `json:"insight_note,omitempty"`
+	Tags        []Tag      `json:"tags" gorm:"many2many:leetcode_problem_tags;joinForeignKey:ProblemID;joinReferences:TagID"`
 
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Number and TopicTags come from LeetCode when the problem is imported.
+	Number    *int     `json:"number,omitempty"`
+	TopicTags []string `json:"topic_tags,omitempty" gorm:"serializer:json"`
+
+	// LastReviewedAt and SchedulerState hold what the schedulers need
+	// besides the SM-2 fields above.
+	LastReviewedAt *time.Time     `json:"last_reviewed_at,omitempty"`
+	SchedulerState SchedulerState `json:"scheduler_state" gorm:"serializer:json"`
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"`
 }
 
 func (p *LeetCodeProblem) BeforeCreate(tx *gorm.DB) (err error) {
@@ -40,3 +49,132 @@ func (p *LeetCodeProblem) BeforeCreate(tx *gorm.DB) (err error) {
 	}
 	return
 }
+
+// Tag labels problems. The names of Patterns are canonical tags shared by
+// everyone and have no UserID; any other name is a tag of its user.
+type Tag struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey"`
+	UserID    *uuid.UUID `json:"user_id,omitempty" gorm:"type:uuid;uniqueIndex:idx_leetcode_tag_name"`
+	Name      string     `json:"name" gorm:"not null;uniqueIndex:idx_leetcode_tag_name"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (Tag) TableName() string {
+	return "leetcode_tags"
+}
+
+func (t *Tag) BeforeCreate(tx *gorm.DB) (err error) {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return
+}
+
+// SchedulerState is the algorithm-specific state of a problem. SM-2 keeps
+// everything in the problem columns; FSRS is nil until the problem is first
+// scheduled by FSRS and is cleared whenever SM-2 schedules it, so switching
+// back to FSRS starts again from the SM-2 state.
+type SchedulerState struct {
+	FSRS *FSRSState `json:"fsrs,omitempty"`
+}
+
+// FSRSState is the memory model of FSRS: Stability is the number of days
+// after which recall drops to 90%, Difficulty ranges from 1 to 10.
+type FSRSState struct {
+	Stability  float64 `json:"stability"`
+	Difficulty float64 `json:"difficulty"`
+	Reps       int     `json:"reps"`
+	Lapses     int     `json:"lapses"`
+}
+
+// ReviewSettings is the user's choice of scheduling algorithm and the
+// limits of the daily review session. Users without a row use
+// DefaultReviewSettings.
+type ReviewSettings struct {
+	UserID           uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey"`
+	Algorithm        Algorithm `json:"algorithm" gorm:"not null"`
+	DesiredRetention float64   `json:"desired_retention" gorm:"not null"`
+	MaxReviewsPerDay int       `json:"max_reviews_per_day" gorm:"not null;default:50"`
+	MaxNewPerDay     int       `json:"max_new_per_day" gorm:"not null;default:5"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+func (ReviewSettings) TableName() string {
+	return "leetcode_review_settings"
+}
+
+func DefaultReviewSettings(userID uuid.UUID) *ReviewSettings {
+	return &ReviewSettings{
+		UserID:           userID,
+		Algorithm:        AlgorithmSM2,
+		DesiredRetention: 0.9,
+		MaxReviewsPerDay: 50,
+		MaxNewPerDay:     5,
+	}
+}
+
+// ReviewSession is the review queue of a user for one day. It is built on
+// the first request of the day and kept stable afterwards; reviews of its
+// problems are recorded in CompletedIDs.
+type ReviewSession struct {
+	ID           uuid.UUID   `json:"id" gorm:"type:uuid;primaryKey"`
+	UserID       uuid.UUID   `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_review_session_day"`
+	Day          string      `json:"day" gorm:"not null;uniqueIndex:idx_review_session_day"`
+	ProblemIDs   []uuid.UUID `json:"problem_ids" gorm:"serializer:json"`
+	NewIDs       []uuid.UUID `json:"new_ids" gorm:"serializer:json"`
+	CompletedIDs []uuid.UUID `json:"completed_ids" gorm:"serializer:json"`
+	Backlog      int         `json:"backlog" gorm:"default:0"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+}
+
+func (ReviewSession) TableName() string {
+	return "leetcode_review_sessions"
+}
+
+func (s *ReviewSession) BeforeCreate(tx *gorm.DB) (err error) {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return
+}
+
+// ReviewLog records one review of a problem together with the scheduling
+// state before and after it, so the history of a problem survives the
+// overwrite of its current state.
+type ReviewLog struct {
+	ID               uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	ProblemID        uuid.UUID `json:"problem_id" gorm:"type:uuid;index:idx_review_logs_problem,priority:1;not null"`
+	UserID           uuid.UUID `json:"user_id" gorm:"type:uuid;index;not null"`
+	Algorithm        Algorithm `json:"algorithm" gorm:"not null;default:SM2"`
+	Score            int       `json:"score" gorm:"not null"`
+	PrevLastScore    int       `json:"prev_last_score" gorm:"not null"`
+	PrevInterval     int       `json:"prev_interval" gorm:"not null"`
+	NewInterval      int       `json:"new_interval" gorm:"not null"`
+	PrevEaseFactor   float64   `json:"prev_ease_factor" gorm:"not null"`
+	NewEaseFactor    float64   `json:"new_ease_factor" gorm:"not null"`
+	PrevNextReview   time.Time `json:"prev_next_review" gorm:"not null"`
+	NewNextReview    time.Time `json:"new_next_review" gorm:"not null"`
+	TimeSpentSeconds *int      `json:"time_spent_seconds,omitempty"`
+	Note             *string   `json:"note,omitempty"`
+	ReviewedAt       time.Time `json:"reviewed_at" gorm:"index:idx_review_logs_problem,priority:2;not null"`
+	CreatedAt        time.Time `json:"created_at"`
+
+	PrevLastReviewedAt *time.Time     `json:"prev_last_reviewed_at,omitempty"`
+	PrevSchedulerState SchedulerState `json:"prev_scheduler_state" gorm:"serializer:json"`
+	// PrevInsightNote is the problem's note before a review that replaced it
+	// with Note.
+	PrevInsightNote *string `json:"prev_insight_note,omitempty"`
+}
+
+func (ReviewLog) TableName() string {
+	return "leetcode_review_logs"
+}
+
+func (l *ReviewLog) BeforeCreate(tx *gorm.DB) (err error) {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,168 @@
+package leetcode
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Upper bounds of the daily session limits in ReviewSettings.
+const (
+	maxReviewsPerDay = 500
+	maxNewPerDay     = 100
+)
+
+// isNew reports whether the problem was never reviewed.
+func isNew(p *LeetCodeProblem) bool {
+	return p.LastReviewedAt == nil && p.LastScore == 0
+}
+
+// overdueness is how late a review is relative to its interval: a problem
+// one day late on a one-day interval is at more risk of being forgotten than
+// one a day late on a sixty-day interval.
+func overdueness(p *LeetCodeProblem, now time.Time) float64 {
+	late := now.Sub(p.NextReview).Hours() / 24
+	interval := float64(p.Interval)
+	if interval < 1 {
+		interval = 1
+	}
+	return late / interval
+}
+
+// buildQueue picks the problems of a daily session. Due reviews are taken
+// by overdueness up to maxReviews; the ones left out stay due and compete
+// again tomorrow, which spreads a backlog over the coming days instead of
+// showing it all at once. Up to maxNew new problems are spread evenly among
+// the reviews, and the result is interleaved by tag and difficulty.
+func buildQueue(due, fresh []LeetCodeProblem, maxReviews, maxNew int, now time.Time) (queue []LeetCodeProblem, backlog int) {
+	sort.SliceStable(due, func(i, j int) bool {
+		oi, oj := overdueness(&due[i], now), overdueness(&due[j], now)
+		if oi != oj {
+			return oi > oj
+		}
+		return due[i].NextReview.Before(due[j].NextReview)
+	})
+
+	if maxReviews < 0 {
+		maxReviews = 0
+	}
+	if len(due) > maxReviews {
+		backlog = len(due) - maxReviews
+		due = due[:maxReviews]
+	}
+
+	if maxNew < 0 {
+		maxNew = 0
+	}
+	if len(fresh) > maxNew {
+		fresh = fresh[:maxNew]
+	}
+
+	merged := make([]LeetCodeProblem, 0, len(due)+len(fresh))
+	step := float64(len(due)+len(fresh)) / float64(len(fresh)+1)
+	next := 0
+	for i := range fresh {
+		at := int(step * float64(i+1))
+		for len(merged) < at && next < len(due) {
+			merged = append(merged, due[next])
+			next++
+		}
+		merged = append(merged, fresh[i])
+	}
+	merged = append(merged, due[next:]...)
+
+	return interleave(merged), backlog
+}
+
+// interleave reorders the problems so consecutive ones share no tag and
+// differ in difficulty whenever possible. Each position takes a remaining
+// problem that differs from the previous one in both, then in tags only,
+// then any. Among those it prefers the problem whose tags and difficulty are
+// the most common among the remaining ones, so the largest groups are spread
+// out before they are all that is left, and otherwise keeps the given
+// priority.
+func interleave(problems []LeetCodeProblem) []LeetCodeProblem {
+	remaining := append([]LeetCodeProblem(nil), problems...)
+	ordered := make([]LeetCodeProblem, 0, len(problems))
+
+	tagCount := make(map[uuid.UUID]int)
+	difficultyCount := make(map[Difficulty]int)
+	for i := range remaining {
+		for _, tag := range remaining[i].Tags {
+			tagCount[tag.ID]++
+		}
+		difficultyCount[remaining[i].Difficulty]++
+	}
+	pressure := func(p *LeetCodeProblem) int {
+		n := difficultyCount[p.Difficulty] - 1
+		for _, tag := range p.Tags {
+			n += tagCount[tag.ID] - 1
+		}
+		return n
+	}
+
+	for len(remaining) > 0 {
+		pick := 0
+		if len(ordered) > 0 {
+			prev := ordered[len(ordered)-1]
+			fits := []func(p *LeetCodeProblem) bool{
+				func(p *LeetCodeProblem) bool { return !sharesTag(p, &prev) && p.Difficulty != prev.Difficulty },
+				func(p *LeetCodeProblem) bool { return !sharesTag(p, &prev) },
+			}
+
+			pick = -1
+			for _, fit := range fits {
+				best := -1
+				for i := range remaining {
+					if !fit(&remaining[i]) {
+						continue
+					}
+					if p := pressure(&remaining[i]); p > best {
+						pick, best = i, p
+					}
+				}
+				if pick >= 0 {
+					break
+				}
+			}
+			if pick < 0 {
+				pick = 0
+			}
+		}
+
+		for _, tag := range remaining[pick].Tags {
+			tagCount[tag.ID]--
+		}
+		difficultyCount[remaining[pick].Difficulty]--
+		ordered = append(ordered, remaining[pick])
+		remaining = append(remaining[:pick], remaining[pick+1:]...)
+	}
+
+	return ordered
+}
+
+func problemIDs(problems []LeetCodeProblem) []uuid.UUID {
+	ids := make([]uuid.UUID, len(problems))
+	for i := range problems {
+		ids[i] = problems[i].ID
+	}
+	return ids
+}
+
+func containsID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// dayBounds returns the start and end of the day of t in loc, together with
+// the day as YYYY-MM-DD.
+func dayBounds(t time.Time, loc *time.Location) (start, end time.Time, day string) {
+	local := t.In(loc)
+	start = time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	return start, start.AddDate(0, 0, 1), start.Format("2006-01-02")
+}
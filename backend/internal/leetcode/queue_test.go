@@ -0,0 +1,183 @@
+package leetcode
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var queueNow = time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+func queueProblem(title string, difficulty Difficulty, tags ...string) LeetCodeProblem {
+	problem := LeetCodeProblem{ID: uuid.New(), Title: title, Difficulty: difficulty, Interval: 1}
+	for _, tag := range tags {
+		problem.Tags = append(problem.Tags, Tag{ID: uuid.NewSHA1(uuid.Nil, []byte(tag)), Name: tag})
+	}
+	return problem
+}
+
+func titles(problems []LeetCodeProblem) []string {
+	result := make([]string, len(problems))
+	for i := range problems {
+		result[i] = problems[i].Title
+	}
+	return result
+}
+
+func TestBuildQueueCapsReviewsByOverdueness(t *testing.T) {
+	due := make([]LeetCodeProblem, 80)
+	for i := range due {
+		due[i] = queueProblem(fmt.Sprintf("due-%02d", i), DifficultyMedium, fmt.Sprintf("tag-%02d", i))
+		due[i].NextReview = queueNow.AddDate(0, 0, -(i + 1))
+	}
+
+	queue, backlog := buildQueue(due, nil, 50, 10, queueNow)
+
+	if len(queue) != 50 || backlog != 30 {
+		t.Fatalf("len(queue), backlog = %d, %d, want 50, 30", len(queue), backlog)
+	}
+	// The most overdue are due-79 (80 days late) down to due-30.
+	for _, p := range queue {
+		var n int
+		fmt.Sscanf(p.Title, "due-%d", &n)
+		if n < 30 {
+			t.Errorf("queue has %s, which is less overdue than the ones left out", p.Title)
+		}
+	}
+}
+
+func TestBuildQueuePrefersShortIntervals(t *testing.T) {
+	// Both are two days late, but on a one-day interval that is a bigger
+	// risk than on a thirty-day one.
+	long := queueProblem("long", DifficultyEasy, "a")
+	long.Interval = 30
+	long.NextReview = queueNow.AddDate(0, 0, -2)
+	short := queueProblem("short", DifficultyHard, "b")
+	short.NextReview = queueNow.AddDate(0, 0, -2)
+
+	queue, backlog := buildQueue([]LeetCodeProblem{long, short}, nil, 1, 0, queueNow)
+
+	if got := titles(queue); len(got) != 1 || got[0] != "short" || backlog != 1 {
+		t.Errorf("queue, backlog = %v, %d, want [short], 1", got, backlog)
+	}
+}
+
+func TestBuildQueueSpreadsNewProblems(t *testing.T) {
+	// Every problem has its own tag and the same difficulty, so interleaving
+	// keeps the merged order.
+	due := make([]LeetCodeProblem, 8)
+	for i := range due {
+		due[i] = queueProblem(fmt.Sprintf("due-%d", i), DifficultyMedium, fmt.Sprintf("due-%d", i))
+		due[i].NextReview = queueNow.Add(-time.Duration(8-i) * time.Hour)
+	}
+	fresh := make([]LeetCodeProblem, 5)
+	for i := range fresh {
+		fresh[i] = queueProblem(fmt.Sprintf("new-%d", i), DifficultyMedium, fmt.Sprintf("new-%d", i))
+	}
+
+	queue, backlog := buildQueue(due, fresh, 20, 2, queueNow)
+
+	want := []string{"due-0", "due-1", "due-2", "new-0", "due-3", "due-4", "new-1", "due-5", "due-6", "due-7"}
+	got := titles(queue)
+	if backlog != 0 || fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("queue, backlog = %v, %d, want %v, 0", got, backlog, want)
+	}
+}
+
+func TestBuildQueueOnlyNewProblems(t *testing.T) {
+	fresh := []LeetCodeProblem{
+		queueProblem("new-0", DifficultyEasy, "a"),
+		queueProblem("new-1", DifficultyMedium, "b"),
+		queueProblem("new-2", DifficultyHard, "c"),
+	}
+
+	queue, backlog := buildQueue(nil, fresh, 0, 2, queueNow)
+
+	if got := titles(queue); fmt.Sprint(got) != "[new-0 new-1]" || backlog != 0 {
+		t.Errorf("queue, backlog = %v, %d, want [new-0 new-1], 0", got, backlog)
+	}
+}
+
+func TestInterleave(t *testing.T) {
+	tests := []struct {
+		name     string
+		problems []LeetCodeProblem
+		want     []string
+	}{
+		{
+			name: "avoids shared tags and difficulties",
+			problems: []LeetCodeProblem{
+				queueProblem("a-easy", DifficultyEasy, "a"),
+				queueProblem("a-medium", DifficultyMedium, "a"),
+				queueProblem("a-hard", DifficultyHard, "a"),
+				queueProblem("b-easy", DifficultyEasy, "b"),
+				queueProblem("b-medium", DifficultyMedium, "b"),
+				queueProblem("c-hard", DifficultyHard, "c"),
+			},
+			want: []string{"a-easy", "b-medium", "a-hard", "b-easy", "a-medium", "c-hard"},
+		},
+		{
+			name: "any shared tag counts",
+			problems: []LeetCodeProblem{
+				queueProblem("ab", DifficultyEasy, "a", "b"),
+				queueProblem("bc", DifficultyMedium, "b", "c"),
+				queueProblem("cd", DifficultyHard, "c", "d"),
+				queueProblem("d", DifficultyMedium, "d"),
+			},
+			want: []string{"ab", "cd", "bc", "d"},
+		},
+		{
+			name: "differing tags beat differing difficulty",
+			problems: []LeetCodeProblem{
+				queueProblem("a-easy", DifficultyEasy, "a"),
+				queueProblem("a-medium", DifficultyMedium, "a"),
+				queueProblem("b-easy", DifficultyEasy, "b"),
+			},
+			want: []string{"a-easy", "b-easy", "a-medium"},
+		},
+		{
+			name: "keeps the priority when nothing can be avoided",
+			problems: []LeetCodeProblem{
+				queueProblem("first", DifficultyEasy, "a"),
+				queueProblem("second", DifficultyEasy, "a"),
+				queueProblem("third", DifficultyMedium, "a"),
+			},
+			want: []string{"first", "second", "third"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := interleave(tt.problems)
+			if fmt.Sprint(titles(got)) != fmt.Sprint(tt.want) {
+				t.Errorf("interleave = %v, want %v", titles(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestInterleaveNoAdjacentConflicts(t *testing.T) {
+	// Sorted by priority the same tags and difficulties come in runs, but
+	// they can be arranged with no two neighbours alike.
+	difficulties := []Difficulty{DifficultyEasy, DifficultyMedium, DifficultyHard}
+	var problems []LeetCodeProblem
+	for _, tag := range []string{"a", "b", "c"} {
+		for i, difficulty := range difficulties {
+			problems = append(problems, queueProblem(fmt.Sprintf("%s-%d", tag, i), difficulty, tag))
+		}
+	}
+
+	got := interleave(problems)
+
+	if len(got) != len(problems) {
+		t.Fatalf("interleave returned %d problems, want %d", len(got), len(problems))
+	}
+	for i := 1; i < len(got); i++ {
+		prev, cur := &got[i-1], &got[i]
+		if sharesTag(prev, cur) || prev.Difficulty == cur.Difficulty {
+			t.Errorf("%s and %s are adjacent in %v", prev.Title, cur.Title, titles(got))
+		}
+	}
+}
@@ -2,19 +2,75 @@ package leetcode
 
 import (
 	"context"
+	"database/sql"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository interface {
+	Transaction(ctx context.Context, fn func(repo Repository) error) error
 	Create(ctx context.Context, problem *LeetCodeProblem) error
+	CreateMany(ctx context.Context, problems []LeetCodeProblem) error
 	GetByID(ctx context.Context, id, userID uuid.UUID) (*LeetCodeProblem, error)
-	GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]LeetCodeProblem, error)
-	GetDueProblems(ctx context.Context, userID uuid.UUID) ([]LeetCodeProblem, error)
+	GetURLs(ctx context.Context, userID uuid.UUID) ([]string, error)
+	GetAllByUserID(ctx context.Context, userID uuid.UUID, filter ProblemFilter) ([]LeetCodeProblem, error)
+	GetDueProblems(ctx context.Context, userID uuid.UUID, filter ProblemFilter) ([]LeetCodeProblem, error)
+	GetScheduledBetween(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]LeetCodeProblem, error)
 	Update(ctx context.Context, problem *LeetCodeProblem) error
 	Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+	GetTrash(ctx context.Context, userID uuid.UUID) ([]LeetCodeProblem, error)
+	Restore(ctx context.Context, id uuid.UUID, userID uuid.UUID) (bool, error)
+
+	GetTags(ctx context.Context, userID uuid.UUID) ([]TagResponseDTO, error)
+	GetUserTagsByName(ctx context.Context, userID uuid.UUID, names []string) ([]Tag, error)
+	CreateTag(ctx context.Context, tag *Tag) error
+	ReplaceTags(ctx context.Context, problem *LeetCodeProblem, tags []Tag) error
+
+	GetPatternStats(ctx context.Context, userID uuid.UUID, dueBefore time.Time) ([]StatsRow, error)
+	GetDifficultyStats(ctx context.Context, userID uuid.UUID, dueBefore time.Time) ([]StatsRow, error)
+
+	GetReviewSettings(ctx context.Context, userID uuid.UUID) (*ReviewSettings, error)
+	SaveReviewSettings(ctx context.Context, settings *ReviewSettings) error
+
+	GetByIDs(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) ([]LeetCodeProblem, error)
+	GetDueReviews(ctx context.Context, userID uuid.UUID, before time.Time) ([]LeetCodeProblem, error)
+	GetNewProblems(ctx context.Context, userID uuid.UUID, limit int) ([]LeetCodeProblem, error)
+	CountReviewsSince(ctx context.Context, userID uuid.UUID, since time.Time) (ReviewCount, error)
+	GetSession(ctx context.Context, userID uuid.UUID, day string) (*ReviewSession, error)
+	GetSessionForUpdate(ctx context.Context, userID uuid.UUID, day string) (*ReviewSession, error)
+	CreateSession(ctx context.Context, session *ReviewSession) error
+	UpdateSession(ctx context.Context, session *ReviewSession) error
+
+	CreateReviewLog(ctx context.Context, log *ReviewLog) error
+	GetReviewLogs(ctx context.Context, problemID, userID uuid.UUID) ([]ReviewLog, error)
+	GetLatestReviewLog(ctx context.Context, problemID, userID uuid.UUID) (*ReviewLog, error)
+	DeleteReviewLog(ctx context.Context, id, userID uuid.UUID) error
+}
+
+// ReviewCount is the number of reviews made in a period and how many of
+// them were the first review of a problem.
+type ReviewCount struct {
+	Reviews     int
+	NewProblems int
+}
+
+// StatsRow aggregates the problems of a pattern or a difficulty, or all of
+// them when Overall is set. Averages are nil when there is nothing to
+// average.
+type StatsRow struct {
+	Key           string
+	Overall       bool
+	Problems      int
+	Reviewed      int
+	AvgLastScore  *float64
+	AvgEaseFactor *float64
+	LapseRate     *float64
+	DueSoon       int
+	Mastery       *float64
 }
 
 type repository struct {
@@ -25,14 +81,47 @@ func NewRepository(db *gorm.DB) Repository {
 	return &repository{db: db}
 }
 
+// Transaction runs fn with a repository bound to a database transaction.
+func (r *repository) Transaction(ctx context.Context, fn func(repo Repository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&repository{db: tx})
+	})
+}
+
+func orderedTags(db *gorm.DB) *gorm.DB {
+	return db.Order("leetcode_tags.name ASC")
+}
+
+// withTags keeps the problems that have every one of the tags.
+func withTags(tags []string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, tag := range tags {
+			db = db.Where(`EXISTS (
+				SELECT 1 FROM leetcode_problem_tags pt
+				JOIN leetcode_tags t ON t.id = pt.tag_id
+				WHERE pt.problem_id = leet_code_problems.id AND LOWER(t.name) = LOWER(?)
+			)`, strings.TrimSpace(tag))
+		}
+		return db
+	}
+}
+
 func (r *repository) Create(ctx context.Context, problem *LeetCodeProblem) error {
 	return r.db.WithContext(ctx).Create(problem).Error
 }
 
+// createBatchSize bounds the rows of each INSERT of a bulk import.
+const createBatchSize = 100
+
+func (r *repository) CreateMany(ctx context.Context, problems []LeetCodeProblem) error {
+	return r.db.WithContext(ctx).CreateInBatches(&problems, createBatchSize).Error
+}
+
 func (r *repository) GetByID(ctx context.Context, id, userID uuid.UUID) (*LeetCodeProblem, error) {
 	var problem LeetCodeProblem
 
 	err := r.db.WithContext(ctx).
+		Preload("Tags", orderedTags).
 		Where("id = ? AND user_id = ?", id, userID).
 		First(&problem).Error
 
@@ -43,10 +132,30 @@ func (r *repository) GetByID(ctx context.Context, id, userID uuid.UUID) (*LeetCo
 	return &problem, nil
 }
 
-func (r *repository) GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]LeetCodeProblem, error) {
+// GetURLs returns the URLs of every problem of the user, including the ones
+// in the trash.
+func (r *repository) GetURLs(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	var urls []string
+
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&LeetCodeProblem{}).
+		Where("user_id = ?", userID).
+		Pluck("url", &urls).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return urls, nil
+}
+
+func (r *repository) GetAllByUserID(ctx context.Context, userID uuid.UUID, filter ProblemFilter) ([]LeetCodeProblem, error) {
 	var problems []LeetCodeProblem
 
 	err := r.db.WithContext(ctx).
+		Preload("Tags", orderedTags).
+		Scopes(withTags(filter.Tags)).
 		Where("user_id = ?", userID).
 		Order("next_review ASC").
 		Find(&problems).Error
@@ -58,11 +167,13 @@ func (r *repository) GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]Le
 	return problems, nil
 }
 
-func (r *repository) GetDueProblems(ctx context.Context, userID uuid.UUID) ([]LeetCodeProblem, error) {
+func (r *repository) GetDueProblems(ctx context.Context, userID uuid.UUID, filter ProblemFilter) ([]LeetCodeProblem, error) {
 	var problems []LeetCodeProblem
 	now := time.Now()
 
 	err := r.db.WithContext(ctx).
+		Preload("Tags", orderedTags).
+		Scopes(withTags(filter.Tags)).
 		Where("user_id = ? AND next_review <= ?", userID, now).
 		Order("next_review ASC").
 		Find(&problems).Error
@@ -74,12 +185,372 @@ func (r *repository) GetDueProblems(ctx context.Context, userID uuid.UUID) ([]Le
 	return problems, nil
 }
 
+func (r *repository) GetScheduledBetween(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]LeetCodeProblem, error) {
+	var problems []LeetCodeProblem
+
+	err := r.db.WithContext(ctx).
+		Preload("Tags", orderedTags).
+		Where("user_id = ? AND next_review >= ? AND next_review < ?", userID, from, to).
+		Order("next_review ASC").
+		Find(&problems).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return problems, nil
+}
+
+// Update saves the problem's own columns; tags change through ReplaceTags.
 func (r *repository) Update(ctx context.Context, problem *LeetCodeProblem) error {
-	return r.db.WithContext(ctx).Save(problem).Error
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(problem).Error
 }
 
+// Delete moves the problem to the trash. Its review state is left untouched
+// so Restore brings the schedule back as it was.
 func (r *repository) Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
 	return r.db.WithContext(ctx).
 		Where("id = ? AND user_id = ?", id, userID).
 		Delete(&LeetCodeProblem{}).Error
 }
+
+func (r *repository) GetTrash(ctx context.Context, userID uuid.UUID) ([]LeetCodeProblem, error) {
+	var problems []LeetCodeProblem
+
+	err := r.db.WithContext(ctx).
+		Preload("Tags", orderedTags).
+		Unscoped().
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+		Order("deleted_at DESC").
+		Find(&problems).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return problems, nil
+}
+
+// Restore takes the problem out of the trash and reports whether it was
+// there.
+func (r *repository) Restore(ctx context.Context, id uuid.UUID, userID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&LeetCodeProblem{}).
+		Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", id, userID).
+		Update("deleted_at", nil)
+
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetTags returns the canonical tags followed by the user's own, each with
+// the number of the user's problems carrying it.
+func (r *repository) GetTags(ctx context.Context, userID uuid.UUID) ([]TagResponseDTO, error) {
+	var tags []TagResponseDTO
+
+	err := r.db.WithContext(ctx).
+		Model(&Tag{}).
+		Select(`leetcode_tags.id, leetcode_tags.name, leetcode_tags.user_id IS NULL AS canonical,
+			COUNT(p.id) AS problems`).
+		Joins("LEFT JOIN leetcode_problem_tags pt ON pt.tag_id = leetcode_tags.id").
+		Joins("LEFT JOIN leet_code_problems p ON p.id = pt.problem_id AND p.user_id = ? AND p.deleted_at IS NULL", userID).
+		Where("leetcode_tags.user_id IS NULL OR leetcode_tags.user_id = ?", userID).
+		Group("leetcode_tags.id").
+		Order("canonical DESC, leetcode_tags.name ASC").
+		Scan(&tags).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// GetUserTagsByName returns the user's own tags whose names match, ignoring
+// case.
+func (r *repository) GetUserTagsByName(ctx context.Context, userID uuid.UUID, names []string) ([]Tag, error) {
+	var tags []Tag
+	if len(names) == 0 {
+		return tags, nil
+	}
+
+	lower := make([]string, len(names))
+	for i, name := range names {
+		lower[i] = strings.ToLower(name)
+	}
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND LOWER(name) IN ?", userID, lower).
+		Find(&tags).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (r *repository) CreateTag(ctx context.Context, tag *Tag) error {
+	return r.db.WithContext(ctx).Create(tag).Error
+}
+
+func (r *repository) ReplaceTags(ctx context.Context, problem *LeetCodeProblem, tags []Tag) error {
+	return r.db.WithContext(ctx).
+		Model(problem).
+		Association("Tags").
+		Replace(tags)
+}
+
+// problemStatsCTE lists the user's live problems with their review and lapse
+// counts from the review log. Reviews scored below recallThreshold (3) are
+// lapses.
+const problemStatsCTE = `
+	WITH problem_stats AS (
+		SELECT p.id, p.difficulty, p.last_score, p.ease_factor, p.interval, p.next_review,
+			(p.last_reviewed_at IS NOT NULL OR p.last_score > 0) AS reviewed,
+			COALESCE(l.reviews, 0) AS reviews,
+			COALESCE(l.lapses, 0) AS lapses
+		FROM leet_code_problems p
+		LEFT JOIN (
+			SELECT problem_id, COUNT(*) AS reviews, COUNT(*) FILTER (WHERE score < 3) AS lapses
+			FROM leetcode_review_logs
+			WHERE user_id = @user
+			GROUP BY problem_id
+		) l ON l.problem_id = p.id
+		WHERE p.user_id = @user AND p.deleted_at IS NULL
+	)`
+
+// statsAggregates computes a StatsRow over the problem_stats rows s of a
+// group. A problem's mastery weighs its last score (40%), its interval up to
+// 60 days (40%) and the share of its reviews that were not lapses (20%);
+// problems never reviewed count as 0, and the group's mastery is the
+// average scaled to 0-100.
+const statsAggregates = `
+	COUNT(s.id) AS problems,
+	COUNT(s.id) FILTER (WHERE s.reviewed) AS reviewed,
+	ROUND(AVG(s.last_score) FILTER (WHERE s.reviewed), 2)::float8 AS avg_last_score,
+	ROUND(AVG(s.ease_factor)::numeric, 2)::float8 AS avg_ease_factor,
+	ROUND(SUM(s.lapses)::numeric / NULLIF(SUM(s.reviews), 0), 4)::float8 AS lapse_rate,
+	COUNT(s.id) FILTER (WHERE s.next_review < @due_before) AS due_soon,
+	ROUND(100 * AVG(
+		CASE WHEN s.reviewed THEN
+			0.4 * GREATEST(s.last_score - 1, 0) / 4.0 +
+			0.4 * LEAST(s.interval, 60) / 60.0 +
+			0.2 * (1 - s.lapses::numeric / GREATEST(s.reviews, 1))
+		ELSE 0 END
+	) FILTER (WHERE s.id IS NOT NULL))::float8 AS mastery`
+
+// GetPatternStats aggregates the user's problems by canonical tag, including
+// patterns without problems, weakest first. A problem counts in each of its
+// patterns.
+func (r *repository) GetPatternStats(ctx context.Context, userID uuid.UUID, dueBefore time.Time) ([]StatsRow, error) {
+	var rows []StatsRow
+
+	err := r.db.WithContext(ctx).Raw(problemStatsCTE+`
+		SELECT t.name AS key, `+statsAggregates+`
+		FROM leetcode_tags t
+		LEFT JOIN (leetcode_problem_tags pt JOIN problem_stats s ON s.id = pt.problem_id) ON pt.tag_id = t.id
+		WHERE t.user_id IS NULL
+		GROUP BY t.name
+		ORDER BY COUNT(s.id) = 0, mastery ASC, t.name ASC`,
+		sql.Named("user", userID), sql.Named("due_before", dueBefore)).
+		Scan(&rows).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// GetDifficultyStats aggregates the user's problems by difficulty, followed
+// by the overall row.
+func (r *repository) GetDifficultyStats(ctx context.Context, userID uuid.UUID, dueBefore time.Time) ([]StatsRow, error) {
+	var rows []StatsRow
+
+	err := r.db.WithContext(ctx).Raw(problemStatsCTE+`
+		SELECT COALESCE(s.difficulty, '') AS key, GROUPING(s.difficulty) = 1 AS overall, `+statsAggregates+`
+		FROM problem_stats s
+		GROUP BY GROUPING SETS ((s.difficulty), ())
+		ORDER BY overall, CASE s.difficulty WHEN 'Easy' THEN 1 WHEN 'Medium' THEN 2 ELSE 3 END`,
+		sql.Named("user", userID), sql.Named("due_before", dueBefore)).
+		Scan(&rows).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *repository) GetReviewSettings(ctx context.Context, userID uuid.UUID) (*ReviewSettings, error) {
+	var settings ReviewSettings
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		First(&settings).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+func (r *repository) SaveReviewSettings(ctx context.Context, settings *ReviewSettings) error {
+	return r.db.WithContext(ctx).Save(settings).Error
+}
+
+func (r *repository) CreateReviewLog(ctx context.Context, log *ReviewLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
+
+// GetReviewLogs returns the reviews of a problem, oldest first.
+func (r *repository) GetReviewLogs(ctx context.Context, problemID, userID uuid.UUID) ([]ReviewLog, error) {
+	var logs []ReviewLog
+
+	err := r.db.WithContext(ctx).
+		Where("problem_id = ? AND user_id = ?", problemID, userID).
+		Order("reviewed_at ASC").
+		Find(&logs).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+func (r *repository) GetLatestReviewLog(ctx context.Context, problemID, userID uuid.UUID) (*ReviewLog, error) {
+	var log ReviewLog
+
+	err := r.db.WithContext(ctx).
+		Where("problem_id = ? AND user_id = ?", problemID, userID).
+		Order("reviewed_at DESC").
+		First(&log).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &log, nil
+}
+
+func (r *repository) DeleteReviewLog(ctx context.Context, id, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		Delete(&ReviewLog{}).Error
+}
+
+func (r *repository) GetByIDs(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) ([]LeetCodeProblem, error) {
+	var problems []LeetCodeProblem
+	if len(ids) == 0 {
+		return problems, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Preload("Tags", orderedTags).
+		Where("user_id = ? AND id IN ?", userID, ids).
+		Find(&problems).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return problems, nil
+}
+
+// GetDueReviews returns the problems reviewed at least once that are due
+// before the given instant.
+func (r *repository) GetDueReviews(ctx context.Context, userID uuid.UUID, before time.Time) ([]LeetCodeProblem, error) {
+	var problems []LeetCodeProblem
+
+	err := r.db.WithContext(ctx).
+		Preload("Tags", orderedTags).
+		Where("user_id = ? AND next_review < ?", userID, before).
+		Where("last_reviewed_at IS NOT NULL OR last_score > 0").
+		Order("next_review ASC").
+		Find(&problems).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return problems, nil
+}
+
+// GetNewProblems returns the problems never reviewed, oldest first.
+func (r *repository) GetNewProblems(ctx context.Context, userID uuid.UUID, limit int) ([]LeetCodeProblem, error) {
+	var problems []LeetCodeProblem
+	if limit <= 0 {
+		return problems, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Preload("Tags", orderedTags).
+		Where("user_id = ? AND last_reviewed_at IS NULL AND last_score = 0", userID).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&problems).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return problems, nil
+}
+
+func (r *repository) CountReviewsSince(ctx context.Context, userID uuid.UUID, since time.Time) (ReviewCount, error) {
+	var count ReviewCount
+
+	err := r.db.WithContext(ctx).
+		Model(&ReviewLog{}).
+		Select("COUNT(*) AS reviews, COUNT(*) FILTER (WHERE prev_last_reviewed_at IS NULL AND prev_last_score = 0) AS new_problems").
+		Where("user_id = ? AND reviewed_at >= ?", userID, since).
+		Scan(&count).Error
+
+	return count, err
+}
+
+func (r *repository) GetSession(ctx context.Context, userID uuid.UUID, day string) (*ReviewSession, error) {
+	var session ReviewSession
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND day = ?", userID, day).
+		First(&session).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// GetSessionForUpdate locks the session row until the end of the
+// transaction, so concurrent reviews update its completed problems in turn.
+func (r *repository) GetSessionForUpdate(ctx context.Context, userID uuid.UUID, day string) (*ReviewSession, error) {
+	var session ReviewSession
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND day = ?", userID, day).
+		First(&session).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// CreateSession stores the session unless another request already created
+// the one of the same day.
+func (r *repository) CreateSession(ctx context.Context, session *ReviewSession) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(session).Error
+}
+
+func (r *repository) UpdateSession(ctx context.Context, session *ReviewSession) error {
+	return r.db.WithContext(ctx).Save(session).Error
+}
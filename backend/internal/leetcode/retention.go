@@ -0,0 +1,95 @@
+package leetcode
+
+import "math"
+
+const (
+	// recallThreshold is the lowest score counted as remembering the
+	// problem; lower scores reset the interval in calculateNextReview.
+	recallThreshold = 3
+
+	minStability     = 0.1
+	maxStability     = 3650.0
+	stabilitySteps   = 100
+	minCurveDays     = 30
+	maxCurveDays     = 365
+	curveSampleCount = 30
+)
+
+// retentionCurve builds the observed recall points of a review history
+// (oldest first) and fits the stability S of R(t) = exp(-t/S) to them by
+// maximum likelihood. The log-likelihood is concave in 1/S, so a ternary
+// search over [1/maxStability, 1/minStability] finds the optimum; when every
+// recall succeeded or failed the fit ends on the respective bound.
+func retentionCurve(logs []ReviewLog, interval int) RetentionCurveDTO {
+	curve := RetentionCurveDTO{
+		Points: []RetentionPointDTO{},
+		Curve:  []RetentionSampleDTO{},
+	}
+
+	var elapsed []float64
+	var recalled []bool
+	for i := 1; i < len(logs); i++ {
+		days := logs[i].ReviewedAt.Sub(logs[i-1].ReviewedAt).Hours() / 24
+		ok := logs[i].Score >= recallThreshold
+
+		curve.Points = append(curve.Points, RetentionPointDTO{
+			ReviewedAt:  logs[i].ReviewedAt,
+			ElapsedDays: math.Round(days*100) / 100,
+			Score:       logs[i].Score,
+			Recalled:    ok,
+		})
+
+		if days > 0 {
+			elapsed = append(elapsed, days)
+			recalled = append(recalled, ok)
+		}
+	}
+
+	if len(elapsed) == 0 {
+		return curve
+	}
+
+	likelihood := func(decay float64) float64 {
+		var total float64
+		for i, t := range elapsed {
+			if recalled[i] {
+				total -= decay * t
+			} else {
+				total += math.Log(-math.Expm1(-decay * t))
+			}
+		}
+		return total
+	}
+
+	lo, hi := 1/maxStability, 1/minStability
+	for i := 0; i < stabilitySteps; i++ {
+		m1 := lo + (hi-lo)/3
+		m2 := hi - (hi-lo)/3
+		if likelihood(m1) < likelihood(m2) {
+			lo = m1
+		} else {
+			hi = m2
+		}
+	}
+
+	stability := math.Round(2/(lo+hi)*100) / 100
+	curve.Stability = &stability
+
+	days := 2 * interval
+	if days < minCurveDays {
+		days = minCurveDays
+	}
+	if days > maxCurveDays {
+		days = maxCurveDays
+	}
+
+	for i := 0; i <= curveSampleCount; i++ {
+		day := days * i / curveSampleCount
+		curve.Curve = append(curve.Curve, RetentionSampleDTO{
+			Day:       day,
+			Retention: math.Round(math.Exp(-float64(day)/stability)*1000) / 1000,
+		})
+	}
+
+	return curve
+}
@@ -0,0 +1,213 @@
+package leetcode
+
+import (
+	"math"
+	"time"
+)
+
+// Scheduler decides when a problem is reviewed next. Schedule applies a
+// review with the given score (1 to 5) made at now, updating LastScore,
+// Interval, NextReview, LastReviewedAt and the scheduler's own state.
+type Scheduler interface {
+	Schedule(problem *LeetCodeProblem, score int, now time.Time)
+}
+
+const (
+	minDesiredRetention = 0.7
+	maxDesiredRetention = 0.97
+	maxIntervalDays     = 36500
+)
+
+// NewScheduler returns the scheduler chosen in the user's settings.
+func NewScheduler(settings *ReviewSettings) Scheduler {
+	if settings.Algorithm == AlgorithmFSRS {
+		return &fsrsScheduler{weights: fsrsDefaultWeights, retention: settings.DesiredRetention}
+	}
+	return sm2Scheduler{}
+}
+
+// sm2Scheduler is the Anki-like SM-2 variant the app started with.
+type sm2Scheduler struct{}
+
+func (sm2Scheduler) Schedule(problem *LeetCodeProblem, score int, now time.Time) {
+	problem.LastScore = score
+
+	// Adjust ease factor based on score (Anki algorithm)
+	problem.EaseFactor = problem.EaseFactor + (0.1 - (5-float64(score))*(0.08+(5-float64(score))*0.02))
+	if problem.EaseFactor < 1.3 {
+		problem.EaseFactor = 1.3
+	}
+
+	// Calculate new interval
+	var newInterval int
+	switch {
+	case score < recallThreshold:
+		// Failed: review tomorrow
+		newInterval = 1
+	case score == 3:
+		// Hard: keep same interval or slightly increase
+		newInterval = int(math.Max(1, float64(problem.Interval)*1.2))
+	case score == 4:
+		// Good: double the interval
+		newInterval = int(math.Max(1, float64(problem.Interval)*problem.EaseFactor))
+	case score == 5:
+		// Easy: triple the interval
+		newInterval = int(math.Max(1, float64(problem.Interval)*problem.EaseFactor*1.3))
+	}
+
+	problem.Interval = newInterval
+	problem.NextReview = now.AddDate(0, 0, newInterval)
+	problem.LastReviewedAt = &now
+	problem.SchedulerState.FSRS = nil
+}
+
+// FSRS rating of a review.
+const (
+	fsrsAgain = 1
+	fsrsHard  = 2
+	fsrsGood  = 3
+	fsrsEasy  = 4
+)
+
+const (
+	fsrsDecay  = -0.5
+	fsrsFactor = 19.0 / 81.0
+)
+
+// fsrsDefaultWeights are the published default parameters of FSRS-4.5.
+var fsrsDefaultWeights = [17]float64{
+	0.4872, 1.4003, 3.7145, 13.8206, 5.1618, 1.2298, 0.8975, 0.031,
+	1.6474, 0.1367, 1.0461, 2.1072, 0.0793, 0.3246, 1.587, 0.2272, 2.8755,
+}
+
+// fsrsScheduler implements FSRS-4.5. The next interval is the number of
+// days after which the predicted recall drops to the desired retention.
+type fsrsScheduler struct {
+	weights   [17]float64
+	retention float64
+}
+
+// fsrsRating maps the 1-5 score to the four FSRS ratings; scores below
+// recallThreshold are lapses, as in SM-2.
+func fsrsRating(score int) int {
+	switch {
+	case score < recallThreshold:
+		return fsrsAgain
+	case score == 3:
+		return fsrsHard
+	case score == 4:
+		return fsrsGood
+	default:
+		return fsrsEasy
+	}
+}
+
+func (f *fsrsScheduler) Schedule(problem *LeetCodeProblem, score int, now time.Time) {
+	rating := fsrsRating(score)
+	state := f.current(problem)
+
+	if state == nil {
+		state = &FSRSState{
+			Stability:  f.initialStability(rating),
+			Difficulty: f.initialDifficulty(rating),
+		}
+	} else {
+		elapsed := 0.0
+		if problem.LastReviewedAt != nil && now.After(*problem.LastReviewedAt) {
+			elapsed = now.Sub(*problem.LastReviewedAt).Hours() / 24
+		}
+		recall := f.retrievability(elapsed, state.Stability)
+
+		if rating == fsrsAgain {
+			state.Stability = f.forgetStability(state.Difficulty, state.Stability, recall)
+		} else {
+			state.Stability = f.recallStability(state.Difficulty, state.Stability, recall, rating)
+		}
+		state.Difficulty = f.nextDifficulty(state.Difficulty, rating)
+	}
+
+	state.Reps++
+	if rating == fsrsAgain {
+		state.Lapses++
+	}
+
+	problem.LastScore = score
+	problem.Interval = f.interval(state.Stability)
+	problem.NextReview = now.AddDate(0, 0, problem.Interval)
+	problem.LastReviewedAt = &now
+	problem.SchedulerState.FSRS = state
+}
+
+// current returns a copy of the problem's FSRS state. Problems reviewed
+// before with SM-2 are seeded from it: the current interval as stability
+// and the difficulty of a "good" first answer.
+func (f *fsrsScheduler) current(problem *LeetCodeProblem) *FSRSState {
+	if problem.SchedulerState.FSRS != nil {
+		state := *problem.SchedulerState.FSRS
+		return &state
+	}
+	if problem.LastReviewedAt == nil && problem.LastScore == 0 {
+		return nil
+	}
+
+	if problem.LastReviewedAt == nil {
+		reviewed := problem.NextReview.AddDate(0, 0, -problem.Interval)
+		problem.LastReviewedAt = &reviewed
+	}
+	return &FSRSState{
+		Stability:  math.Max(float64(problem.Interval), 0.1),
+		Difficulty: f.initialDifficulty(fsrsGood),
+	}
+}
+
+func (f *fsrsScheduler) initialStability(rating int) float64 {
+	return math.Max(f.weights[rating-1], 0.1)
+}
+
+func (f *fsrsScheduler) initialDifficulty(rating int) float64 {
+	return clampDifficulty(f.weights[4] - float64(rating-3)*f.weights[5])
+}
+
+func (f *fsrsScheduler) nextDifficulty(difficulty float64, rating int) float64 {
+	next := difficulty - f.weights[6]*float64(rating-3)
+	// Mean reversion towards the difficulty of a "good" first answer.
+	return clampDifficulty(f.weights[7]*f.initialDifficulty(fsrsGood) + (1-f.weights[7])*next)
+}
+
+func (f *fsrsScheduler) retrievability(elapsedDays, stability float64) float64 {
+	return math.Pow(1+fsrsFactor*elapsedDays/stability, fsrsDecay)
+}
+
+func (f *fsrsScheduler) recallStability(difficulty, stability, recall float64, rating int) float64 {
+	hardPenalty, easyBonus := 1.0, 1.0
+	if rating == fsrsHard {
+		hardPenalty = f.weights[15]
+	}
+	if rating == fsrsEasy {
+		easyBonus = f.weights[16]
+	}
+
+	growth := math.Exp(f.weights[8]) *
+		(11 - difficulty) *
+		math.Pow(stability, -f.weights[9]) *
+		(math.Exp(f.weights[10]*(1-recall)) - 1) *
+		hardPenalty * easyBonus
+
+	return stability * (growth + 1)
+}
+
+func (f *fsrsScheduler) forgetStability(difficulty, stability, recall float64) float64 {
+	return f.weights[11] *
+		math.Pow(difficulty, -f.weights[12]) *
+		(math.Pow(stability+1, f.weights[13]) - 1) *
+		math.Exp(f.weights[14]*(1-recall))
+}
+
+func (f *fsrsScheduler) interval(stability float64) int {
+	days := stability / fsrsFactor * (math.Pow(f.retention, 1/fsrsDecay) - 1)
+	return int(math.Min(math.Max(math.Round(days), 1), maxIntervalDays))
+}
+
+func clampDifficulty(difficulty float64) float64 {
+	return math.Min(math.Max(difficulty, 1), 10)
+}
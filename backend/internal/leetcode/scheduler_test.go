@@ -0,0 +1,158 @@
+package leetcode
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var reviewTime = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+func TestSM2Schedule(t *testing.T) {
+	tests := []struct {
+		name         string
+		easeFactor   float64
+		interval     int
+		score        int
+		wantEase     float64
+		wantInterval int
+	}{
+		{"new problem good", 2.5, 1, 4, 2.5, 2},
+		{"easy grows by ease and bonus", 2.5, 10, 5, 2.6, 33},
+		{"hard grows by 20%", 2.5, 10, 3, 2.36, 12},
+		{"lapse resets interval", 2.5, 30, 2, 2.18, 1},
+		{"ease factor floor", 1.35, 30, 1, 1.3, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem := &LeetCodeProblem{
+				EaseFactor:     tt.easeFactor,
+				Interval:       tt.interval,
+				SchedulerState: SchedulerState{FSRS: &FSRSState{Stability: 5, Difficulty: 5}},
+			}
+
+			NewScheduler(&ReviewSettings{Algorithm: AlgorithmSM2}).Schedule(problem, tt.score, reviewTime)
+
+			if math.Abs(problem.EaseFactor-tt.wantEase) > 1e-9 {
+				t.Errorf("ease factor = %v, want %v", problem.EaseFactor, tt.wantEase)
+			}
+			if problem.Interval != tt.wantInterval {
+				t.Errorf("interval = %d, want %d", problem.Interval, tt.wantInterval)
+			}
+			if want := reviewTime.AddDate(0, 0, tt.wantInterval); !problem.NextReview.Equal(want) {
+				t.Errorf("next review = %v, want %v", problem.NextReview, want)
+			}
+			if problem.LastScore != tt.score {
+				t.Errorf("last score = %d, want %d", problem.LastScore, tt.score)
+			}
+			if problem.SchedulerState.FSRS != nil {
+				t.Error("SM-2 should clear the FSRS state")
+			}
+		})
+	}
+}
+
+func TestFSRSFirstReview(t *testing.T) {
+	tests := []struct {
+		score          int
+		wantInterval   int
+		wantStability  float64
+		wantDifficulty float64
+		wantLapses     int
+	}{
+		{1, 1, 0.4872, 7.6214, 1},
+		{3, 1, 1.4003, 6.3916, 0},
+		{4, 4, 3.7145, 5.1618, 0},
+		{5, 14, 13.8206, 3.932, 0},
+	}
+
+	for _, tt := range tests {
+		problem := &LeetCodeProblem{EaseFactor: 2.5, Interval: 1}
+
+		NewScheduler(&ReviewSettings{Algorithm: AlgorithmFSRS, DesiredRetention: 0.9}).Schedule(problem, tt.score, reviewTime)
+
+		state := problem.SchedulerState.FSRS
+		if state == nil {
+			t.Fatalf("score %d: FSRS state not stored", tt.score)
+		}
+		if problem.Interval != tt.wantInterval {
+			t.Errorf("score %d: interval = %d, want %d", tt.score, problem.Interval, tt.wantInterval)
+		}
+		if math.Abs(state.Stability-tt.wantStability) > 1e-9 {
+			t.Errorf("score %d: stability = %v, want %v", tt.score, state.Stability, tt.wantStability)
+		}
+		if math.Abs(state.Difficulty-tt.wantDifficulty) > 1e-9 {
+			t.Errorf("score %d: difficulty = %v, want %v", tt.score, state.Difficulty, tt.wantDifficulty)
+		}
+		if state.Reps != 1 || state.Lapses != tt.wantLapses {
+			t.Errorf("score %d: reps/lapses = %d/%d, want 1/%d", tt.score, state.Reps, state.Lapses, tt.wantLapses)
+		}
+		if problem.EaseFactor != 2.5 {
+			t.Errorf("score %d: FSRS should not touch the ease factor", tt.score)
+		}
+	}
+}
+
+func TestFSRSSecondReviewOnTime(t *testing.T) {
+	tests := []struct {
+		score         int
+		wantInterval  int
+		wantStability float64
+	}{
+		{1, 1, 1.4332},
+		{3, 6, 6.2350},
+		{4, 15, 14.8081},
+		{5, 36, 35.6141},
+	}
+
+	for _, tt := range tests {
+		scheduler := NewScheduler(&ReviewSettings{Algorithm: AlgorithmFSRS, DesiredRetention: 0.9})
+		problem := &LeetCodeProblem{EaseFactor: 2.5, Interval: 1}
+
+		scheduler.Schedule(problem, 4, reviewTime)
+		scheduler.Schedule(problem, tt.score, reviewTime.AddDate(0, 0, problem.Interval))
+
+		if problem.Interval != tt.wantInterval {
+			t.Errorf("score %d: interval = %d, want %d", tt.score, problem.Interval, tt.wantInterval)
+		}
+		if got := problem.SchedulerState.FSRS.Stability; math.Abs(got-tt.wantStability) > 1e-4 {
+			t.Errorf("score %d: stability = %v, want %v", tt.score, got, tt.wantStability)
+		}
+		if problem.SchedulerState.FSRS.Reps != 2 {
+			t.Errorf("score %d: reps = %d, want 2", tt.score, problem.SchedulerState.FSRS.Reps)
+		}
+	}
+}
+
+func TestFSRSDesiredRetention(t *testing.T) {
+	intervals := map[float64]int{0.97: 1, 0.9: 4, 0.8: 9, 0.7: 16}
+
+	for retention, want := range intervals {
+		problem := &LeetCodeProblem{EaseFactor: 2.5, Interval: 1}
+
+		NewScheduler(&ReviewSettings{Algorithm: AlgorithmFSRS, DesiredRetention: retention}).Schedule(problem, 4, reviewTime)
+
+		if problem.Interval != want {
+			t.Errorf("retention %v: interval = %d, want %d", retention, problem.Interval, want)
+		}
+	}
+}
+
+func TestFSRSSeedsFromSM2State(t *testing.T) {
+	problem := &LeetCodeProblem{
+		EaseFactor: 2.5,
+		Interval:   10,
+		LastScore:  4,
+		NextReview: reviewTime,
+	}
+
+	NewScheduler(&ReviewSettings{Algorithm: AlgorithmFSRS, DesiredRetention: 0.9}).Schedule(problem, 4, reviewTime)
+
+	if problem.Interval != 34 {
+		t.Errorf("interval = %d, want 34", problem.Interval)
+	}
+	if problem.LastReviewedAt == nil || !problem.LastReviewedAt.Equal(reviewTime) {
+		t.Errorf("last reviewed at = %v, want %v", problem.LastReviewedAt, reviewTime)
+	}
+}
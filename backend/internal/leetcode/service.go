@@ -2,29 +2,49 @@ package leetcode
 
 import (
 	"context"
-	"math"
+	"errors"
+	"io"
+	"log"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos/internal/auth"
 	"github.com/saulo-duarte/chronos/internal/shared/middlewares"
+	"gorm.io/gorm"
 )
 
 type Service interface {
 	Create(ctx context.Context, dto *CreateProblemDTO) (*ProblemResponseDTO, error)
+	Lookup(ctx context.Context, source string) (*ProblemMetadataResponseDTO, error)
+	Import(ctx context.Context, dto *ImportProblemDTO) (*ProblemResponseDTO, error)
+	BulkImport(ctx context.Context, body io.Reader, opts BulkImportOptions) (*BulkImportResultDTO, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*ProblemResponseDTO, error)
-	GetAll(ctx context.Context) ([]ProblemResponseDTO, error)
-	GetDueProblems(ctx context.Context) ([]ProblemResponseDTO, error)
+	GetAll(ctx context.Context, filter ProblemFilter) ([]ProblemResponseDTO, error)
+	GetDueProblems(ctx context.Context, filter ProblemFilter) ([]ProblemResponseDTO, error)
+	GetTags(ctx context.Context) ([]TagResponseDTO, error)
+	GetStats(ctx context.Context) (*StatsResponseDTO, error)
+	GetScheduledBetween(ctx context.Context, from, to time.Time) ([]ProblemResponseDTO, error)
 	Update(ctx context.Context, id uuid.UUID, dto *UpdateProblemDTO) (*ProblemResponseDTO, error)
 	Review(ctx context.Context, id uuid.UUID, dto *ReviewDTO) (*ProblemResponseDTO, error)
+	UndoReview(ctx context.Context, id uuid.UUID) (*ProblemResponseDTO, error)
+	GetReviews(ctx context.Context, id uuid.UUID) (*ReviewHistoryResponseDTO, error)
 	Delete(ctx context.Context, id uuid.UUID) error
+	GetSettings(ctx context.Context) (*ReviewSettingsResponseDTO, error)
+	UpdateSettings(ctx context.Context, dto *UpdateReviewSettingsDTO) (*ReviewSettingsResponseDTO, error)
+	GetSession(ctx context.Context) (*ReviewSessionResponseDTO, error)
+	GetTrash(ctx context.Context) ([]ProblemResponseDTO, error)
+	Restore(ctx context.Context, id uuid.UUID) (*ProblemResponseDTO, error)
 }
 
 type service struct {
 	repository Repository
+	importer   Importer
+	locations  auth.Locator
+	undoWindow time.Duration
 }
 
-func NewService(repository Repository) Service {
-	return &service{repository: repository}
+func NewService(repository Repository, importer Importer, locator auth.Locator, undoWindow time.Duration) Service {
+	return &service{repository: repository, importer: importer, locations: locator, undoWindow: undoWindow}
 }
 
 func (s *service) Create(ctx context.Context, dto *CreateProblemDTO) (*ProblemResponseDTO, error) {
@@ -33,16 +53,27 @@ func (s *service) Create(ctx context.Context, dto *CreateProblemDTO) (*ProblemRe
 		return nil, ErrUnauthorizedAccess
 	}
 
-	if !dto.Pattern.IsValid() {
-		return nil, ErrInvalidPattern
-	}
 	if !dto.Difficulty.IsValid() {
 		return nil, ErrInvalidDifficulty
 	}
+	names, err := withPattern(dto.Pattern, dto.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if names, err = normalizeTagNames(names); err != nil {
+		return nil, err
+	}
 
 	problem := dto.ToEntity(userID)
 
-	if err := s.repository.Create(ctx, problem); err != nil {
+	err = s.repository.Transaction(ctx, func(repo Repository) error {
+		problem.Tags, err = newTagResolver(repo, userID).resolve(ctx, names)
+		if err != nil {
+			return err
+		}
+		return repo.Create(ctx, problem)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -50,6 +81,191 @@ func (s *service) Create(ctx context.Context, dto *CreateProblemDTO) (*ProblemRe
 	return &response, nil
 }
 
+func (s *service) Lookup(ctx context.Context, source string) (*ProblemMetadataResponseDTO, error) {
+	if _, err := middlewares.GetUserIDFromContext(ctx); err != nil {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	metadata, err := s.fetchMetadata(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+
+	response := ToMetadataResponse(*metadata)
+	return &response, nil
+}
+
+func (s *service) Import(ctx context.Context, dto *ImportProblemDTO) (*ProblemResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	metadata, err := s.fetchMetadata(ctx, dto.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(metadata.Patterns)+len(dto.Tags))
+	for _, p := range metadata.Patterns {
+		names = append(names, string(p))
+	}
+	names = append(names, dto.Tags...)
+	if len(names) == 0 {
+		return nil, ErrPatternNotInferred
+	}
+	names, err = normalizeTagNames(names)
+	if err != nil {
+		return nil, err
+	}
+
+	// Problems added by hand may have the URL in another form, and the ones
+	// in the trash would clash on restore.
+	existing, err := s.existingURLs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing[metadata.URL] {
+		return nil, ErrProblemAlreadyAdded
+	}
+
+	create := CreateProblemDTO{
+		Title:       metadata.Title,
+		URL:         metadata.URL,
+		Difficulty:  metadata.Difficulty,
+		InsightNote: dto.InsightNote,
+	}
+	problem := create.ToEntity(userID)
+	if metadata.Number > 0 {
+		number := metadata.Number
+		problem.Number = &number
+	}
+	problem.TopicTags = metadata.TopicTags
+
+	err = s.repository.Transaction(ctx, func(repo Repository) error {
+		problem.Tags, err = newTagResolver(repo, userID).resolve(ctx, names)
+		if err != nil {
+			return err
+		}
+		return repo.Create(ctx, problem)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := ToResponse(*problem)
+	return &response, nil
+}
+
+// BulkImport creates one problem per valid row of a CSV or JSON file. Rows
+// whose URL the user already has, even in the trash, or that repeat a URL
+// within the file, are reported as duplicates and invalid rows as skipped
+// with the reason; the valid rows are created in one transaction. In dry-run
+// mode nothing is written.
+func (s *service) BulkImport(ctx context.Context, body io.Reader, opts BulkImportOptions) (*BulkImportResultDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	if opts.Confidence == 0 {
+		opts.Confidence = defaultConfidence
+	}
+	if opts.Confidence < 1 || opts.Confidence > 5 {
+		return nil, ErrInvalidConfidence
+	}
+
+	rows, err := parseImportRows(body, opts.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.existingURLs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := &BulkImportResultDTO{DryRun: opts.DryRun, Items: make([]BulkImportItemDTO, 0, len(rows))}
+	var (
+		toCreate []LeetCodeProblem
+		created  []int
+	)
+
+	for _, row := range rows {
+		item := BulkImportItemDTO{Line: row.Line, Title: row.Title, Action: ImportCreate}
+
+		problem, reason := toImportedProblem(row, userID, opts, now)
+		switch {
+		case reason != "":
+			item.Action = ImportSkip
+			item.Reason = reason
+			result.Skipped++
+		case existing[problem.URL]:
+			item.URL = problem.URL
+			item.Action = ImportDuplicate
+			item.Reason = "problema já cadastrado"
+			result.Duplicates++
+		default:
+			existing[problem.URL] = true
+			item.URL = problem.URL
+			item.Tags = tagNames(problem.Tags)
+			item.Difficulty = problem.Difficulty
+			item.Confidence = problem.LastScore
+			item.NextReview = &problem.NextReview
+			toCreate = append(toCreate, *problem)
+			created = append(created, len(result.Items))
+			result.Created++
+		}
+
+		result.Items = append(result.Items, item)
+	}
+
+	if opts.DryRun || len(toCreate) == 0 {
+		return result, nil
+	}
+
+	err = s.repository.Transaction(ctx, func(repo Repository) error {
+		resolver := newTagResolver(repo, userID)
+		for i := range toCreate {
+			toCreate[i].Tags, err = resolver.resolve(ctx, tagNames(toCreate[i].Tags))
+			if err != nil {
+				return err
+			}
+		}
+		return repo.CreateMany(ctx, toCreate)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, index := range created {
+		id := toCreate[i].ID
+		result.Items[index].ProblemID = &id
+	}
+
+	return result, nil
+}
+
+// existingURLs returns the URLs of the user's problems, including the ones in
+// the trash that would come back on restore, normalized like the ones being
+// added.
+func (s *service) existingURLs(ctx context.Context, userID uuid.UUID) (map[string]bool, error) {
+	urls, err := s.repository.GetURLs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]bool, len(urls))
+	for _, u := range urls {
+		if normalized, err := normalizeProblemURL(u); err == nil {
+			existing[normalized] = true
+		}
+		existing[u] = true
+	}
+	return existing, nil
+}
+
 func (s *service) GetByID(ctx context.Context, id uuid.UUID) (*ProblemResponseDTO, error) {
 	userID, err := middlewares.GetUserIDFromContext(ctx)
 	if err != nil {
@@ -65,13 +281,13 @@ func (s *service) GetByID(ctx context.Context, id uuid.UUID) (*ProblemResponseDT
 	return &response, nil
 }
 
-func (s *service) GetAll(ctx context.Context) ([]ProblemResponseDTO, error) {
+func (s *service) GetAll(ctx context.Context, filter ProblemFilter) ([]ProblemResponseDTO, error) {
 	userID, err := middlewares.GetUserIDFromContext(ctx)
 	if err != nil {
 		return nil, ErrUnauthorizedAccess
 	}
 
-	problems, err := s.repository.GetAllByUserID(ctx, userID)
+	problems, err := s.repository.GetAllByUserID(ctx, userID, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -79,13 +295,61 @@ func (s *service) GetAll(ctx context.Context) ([]ProblemResponseDTO, error) {
 	return ToResponseList(problems), nil
 }
 
-func (s *service) GetDueProblems(ctx context.Context) ([]ProblemResponseDTO, error) {
+func (s *service) GetDueProblems(ctx context.Context, filter ProblemFilter) ([]ProblemResponseDTO, error) {
 	userID, err := middlewares.GetUserIDFromContext(ctx)
 	if err != nil {
 		return nil, ErrUnauthorizedAccess
 	}
 
-	problems, err := s.repository.GetDueProblems(ctx, userID)
+	problems, err := s.repository.GetDueProblems(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToResponseList(problems), nil
+}
+
+func (s *service) GetTags(ctx context.Context) ([]TagResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	return s.repository.GetTags(ctx, userID)
+}
+
+// statsDueWindow is how far ahead GetStats counts problems as due.
+const statsDueWindow = 7 * 24 * time.Hour
+
+func (s *service) GetStats(ctx context.Context) (*StatsResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	dueBefore := time.Now().Add(statsDueWindow)
+
+	patterns, err := s.repository.GetPatternStats(ctx, userID, dueBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	difficulties, err := s.repository.GetDifficultyStats(ctx, userID, dueBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	response := ToStatsResponse(patterns, difficulties)
+	return &response, nil
+}
+
+func (s *service) GetScheduledBetween(ctx context.Context, from, to time.Time) ([]ProblemResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	problems, err := s.repository.GetScheduledBetween(ctx, userID, from, to)
 	if err != nil {
 		return nil, err
 	}
@@ -108,7 +372,41 @@ func (s *service) Update(ctx context.Context, id uuid.UUID, dto *UpdateProblemDT
 		return nil, err
 	}
 
-	if err := s.repository.Update(ctx, problem); err != nil {
+	var names []string
+	switch {
+	case dto.Tags != nil:
+		names = *dto.Tags
+	case dto.Pattern != nil:
+		for i := range problem.Tags {
+			if _, ok := ParsePattern(problem.Tags[i].Name); !ok {
+				names = append(names, problem.Tags[i].Name)
+			}
+		}
+	}
+	if names, err = withPattern(dto.Pattern, names); err != nil {
+		return nil, err
+	}
+	if names != nil {
+		if names, err = normalizeTagNames(names); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.repository.Transaction(ctx, func(repo Repository) error {
+		if err := repo.Update(ctx, problem); err != nil {
+			return err
+		}
+		if names == nil {
+			return nil
+		}
+
+		tags, err := newTagResolver(repo, userID).resolve(ctx, names)
+		if err != nil {
+			return err
+		}
+		return repo.ReplaceTags(ctx, problem, tags)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -125,19 +423,114 @@ func (s *service) Review(ctx context.Context, id uuid.UUID, dto *ReviewDTO) (*Pr
 	if dto.Score < 1 || dto.Score > 5 {
 		return nil, ErrInvalidScore
 	}
+	if dto.TimeSpentSeconds != nil && *dto.TimeSpentSeconds < 0 {
+		return nil, ErrInvalidTimeSpent
+	}
 
 	problem, err := s.repository.GetByID(ctx, id, userID)
 	if err != nil {
 		return nil, ErrProblemNotFound
 	}
 
-	s.calculateNextReview(problem, dto.Score)
+	settings, err := s.settingsFor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := s.locations.Location(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	entry := &ReviewLog{
+		ProblemID:          problem.ID,
+		UserID:             userID,
+		Algorithm:          settings.Algorithm,
+		Score:              dto.Score,
+		PrevLastScore:      problem.LastScore,
+		PrevInterval:       problem.Interval,
+		PrevEaseFactor:     problem.EaseFactor,
+		PrevNextReview:     problem.NextReview,
+		PrevLastReviewedAt: problem.LastReviewedAt,
+		PrevSchedulerState: problem.SchedulerState,
+		PrevInsightNote:    problem.InsightNote,
+		TimeSpentSeconds:   dto.TimeSpentSeconds,
+		Note:               dto.InsightNote,
+		ReviewedAt:         time.Now(),
+	}
+
+	NewScheduler(settings).Schedule(problem, dto.Score, entry.ReviewedAt)
 
 	if dto.InsightNote != nil {
 		problem.InsightNote = dto.InsightNote
 	}
 
-	if err := s.repository.Update(ctx, problem); err != nil {
+	entry.NewInterval = problem.Interval
+	entry.NewEaseFactor = problem.EaseFactor
+	entry.NewNextReview = problem.NextReview
+
+	err = s.repository.Transaction(ctx, func(repo Repository) error {
+		if err := repo.Update(ctx, problem); err != nil {
+			return err
+		}
+		if err := repo.CreateReviewLog(ctx, entry); err != nil {
+			return err
+		}
+		return markSession(ctx, repo, userID, loc, problem.ID, entry.ReviewedAt, true)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := ToResponse(*problem)
+	return &response, nil
+}
+
+// UndoReview reverts the most recent review of a problem, restoring the
+// scheduling state logged before it, as long as the review is still inside
+// the undo window. The review is removed from the history.
+func (s *service) UndoReview(ctx context.Context, id uuid.UUID) (*ProblemResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	problem, err := s.repository.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, ErrProblemNotFound
+	}
+
+	entry, err := s.repository.GetLatestReviewLog(ctx, id, userID)
+	if err != nil {
+		return nil, ErrNoReviewToUndo
+	}
+
+	if time.Since(entry.ReviewedAt) > s.undoWindow {
+		return nil, ErrUndoWindowExpired
+	}
+
+	problem.LastScore = entry.PrevLastScore
+	problem.EaseFactor = entry.PrevEaseFactor
+	problem.Interval = entry.PrevInterval
+	problem.NextReview = entry.PrevNextReview
+	problem.LastReviewedAt = entry.PrevLastReviewedAt
+	problem.SchedulerState = entry.PrevSchedulerState
+	if entry.Note != nil {
+		problem.InsightNote = entry.PrevInsightNote
+	}
+
+	loc, err := s.locations.Location(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repository.Transaction(ctx, func(repo Repository) error {
+		if err := repo.Update(ctx, problem); err != nil {
+			return err
+		}
+		if err := repo.DeleteReviewLog(ctx, entry.ID, userID); err != nil {
+			return err
+		}
+		return markSession(ctx, repo, userID, loc, problem.ID, entry.ReviewedAt, false)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -145,6 +538,31 @@ func (s *service) Review(ctx context.Context, id uuid.UUID, dto *ReviewDTO) (*Pr
 	return &response, nil
 }
 
+// GetReviews returns the review history of a problem with its fitted
+// retention curve.
+func (s *service) GetReviews(ctx context.Context, id uuid.UUID) (*ReviewHistoryResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	problem, err := s.repository.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, ErrProblemNotFound
+	}
+
+	logs, err := s.repository.GetReviewLogs(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReviewHistoryResponseDTO{
+		ProblemID: problem.ID,
+		Reviews:   ToReviewLogResponseList(logs),
+		Retention: retentionCurve(logs, problem.Interval),
+	}, nil
+}
+
 func (s *service) Delete(ctx context.Context, id uuid.UUID) error {
 	userID, err := middlewares.GetUserIDFromContext(ctx)
 	if err != nil {
@@ -159,6 +577,162 @@ func (s *service) Delete(ctx context.Context, id uuid.UUID) error {
 	return s.repository.Delete(ctx, id, userID)
 }
 
+func (s *service) GetSettings(ctx context.Context) (*ReviewSettingsResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	settings, err := s.settingsFor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := ToReviewSettingsResponse(settings)
+	return &response, nil
+}
+
+// UpdateSettings changes the algorithm used for the next reviews. Problems
+// keep their current schedule until they are reviewed again.
+func (s *service) UpdateSettings(ctx context.Context, dto *UpdateReviewSettingsDTO) (*ReviewSettingsResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	settings, err := s.settingsFor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if dto.Algorithm != nil {
+		settings.Algorithm = *dto.Algorithm
+	}
+	if dto.DesiredRetention != nil {
+		settings.DesiredRetention = *dto.DesiredRetention
+	}
+
+	if dto.MaxReviewsPerDay != nil {
+		settings.MaxReviewsPerDay = *dto.MaxReviewsPerDay
+	}
+	if dto.MaxNewPerDay != nil {
+		settings.MaxNewPerDay = *dto.MaxNewPerDay
+	}
+
+	if !settings.Algorithm.IsValid() ||
+		settings.DesiredRetention < minDesiredRetention || settings.DesiredRetention > maxDesiredRetention ||
+		settings.MaxReviewsPerDay < 1 || settings.MaxReviewsPerDay > maxReviewsPerDay ||
+		settings.MaxNewPerDay < 0 || settings.MaxNewPerDay > maxNewPerDay {
+		return nil, ErrInvalidReviewSettings
+	}
+
+	if err := s.repository.SaveReviewSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+
+	response := ToReviewSettingsResponse(settings)
+	return &response, nil
+}
+
+// GetSession returns today's review queue, building it on the first call of
+// the day in the user's time zone. The limits discount the reviews already
+// made today, so reviewing outside of the session still counts.
+func (s *service) GetSession(ctx context.Context) (*ReviewSessionResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	settings, err := s.settingsFor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := s.locations.Location(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	start, end, day := dayBounds(now, loc)
+
+	session, err := s.repository.GetSession(ctx, userID, day)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if session == nil {
+		done, err := s.repository.CountReviewsSince(ctx, userID, start)
+		if err != nil {
+			return nil, err
+		}
+
+		due, err := s.repository.GetDueReviews(ctx, userID, end)
+		if err != nil {
+			return nil, err
+		}
+
+		maxNew := settings.MaxNewPerDay - done.NewProblems
+		fresh, err := s.repository.GetNewProblems(ctx, userID, maxNew)
+		if err != nil {
+			return nil, err
+		}
+
+		maxReviews := settings.MaxReviewsPerDay - (done.Reviews - done.NewProblems)
+		queue, backlog := buildQueue(due, fresh, maxReviews, maxNew, now)
+
+		session = &ReviewSession{
+			UserID:       userID,
+			Day:          day,
+			ProblemIDs:   problemIDs(queue),
+			NewIDs:       problemIDs(fresh),
+			CompletedIDs: []uuid.UUID{},
+			Backlog:      backlog,
+		}
+		if err := s.repository.CreateSession(ctx, session); err != nil {
+			return nil, err
+		}
+
+		if session, err = s.repository.GetSession(ctx, userID, day); err != nil {
+			return nil, err
+		}
+	}
+
+	problems, err := s.repository.GetByIDs(ctx, userID, session.ProblemIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return toSessionResponse(session, problems, settings.MaxReviewsPerDay), nil
+}
+
+func (s *service) GetTrash(ctx context.Context) ([]ProblemResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	problems, err := s.repository.GetTrash(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToResponseList(problems), nil
+}
+
+func (s *service) Restore(ctx context.Context, id uuid.UUID) (*ProblemResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	restored, err := s.repository.Restore(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !restored {
+		return nil, ErrProblemNotFound
+	}
+
+	return s.GetByID(ctx, id)
+}
+
 func (s *service) applyUpdates(problem *LeetCodeProblem, dto *UpdateProblemDTO) error {
 	if dto.Title != nil {
 		problem.Title = *dto.Title
@@ -166,12 +740,6 @@ func (s *service) applyUpdates(problem *LeetCodeProblem, dto *UpdateProblemDTO)
 	if dto.URL != nil {
 		problem.URL = *dto.URL
 	}
-	if dto.Pattern != nil {
-		if !dto.Pattern.IsValid() {
-			return ErrInvalidPattern
-		}
-		problem.Pattern = *dto.Pattern
-	}
 	if dto.Difficulty != nil {
 		if !dto.Difficulty.IsValid() {
 			return ErrInvalidDifficulty
@@ -184,33 +752,68 @@ func (s *service) applyUpdates(problem *LeetCodeProblem, dto *UpdateProblemDTO)
 	return nil
 }
 
-// calculateNextReview implements Anki-like spaced repetition algorithm
-func (s *service) calculateNextReview(problem *LeetCodeProblem, score int) {
-	problem.LastScore = score
+// fetchMetadata resolves source to a slug and asks the importer for it.
+// Failures talking to LeetCode are logged and reported as
+// ErrLeetCodeUnavailable.
+func (s *service) fetchMetadata(ctx context.Context, source string) (*ProblemMetadata, error) {
+	slug, err := ParseSlug(source)
+	if err != nil {
+		return nil, err
+	}
 
-	// Adjust ease factor based on score (Anki algorithm)
-	problem.EaseFactor = problem.EaseFactor + (0.1 - (5-float64(score))*(0.08+(5-float64(score))*0.02))
-	if problem.EaseFactor < 1.3 {
-		problem.EaseFactor = 1.3
+	metadata, err := s.importer.Fetch(ctx, slug)
+	if err != nil {
+		if errors.Is(err, ErrProblemNotOnLeetCode) {
+			return nil, ErrProblemNotOnLeetCode
+		}
+		log.Printf("Falha ao consultar o LeetCode para %s: %v", slug, err)
+		return nil, ErrLeetCodeUnavailable
 	}
 
-	// Calculate new interval
-	var newInterval int
-	switch {
-	case score < 3:
-		// Failed: review tomorrow
-		newInterval = 1
-	case score == 3:
-		// Hard: keep same interval or slightly increase
-		newInterval = int(math.Max(1, float64(problem.Interval)*1.2))
-	case score == 4:
-		// Good: double the interval
-		newInterval = int(math.Max(1, float64(problem.Interval)*problem.EaseFactor))
-	case score == 5:
-		// Easy: triple the interval
-		newInterval = int(math.Max(1, float64(problem.Interval)*problem.EaseFactor*1.3))
-	}
-
-	problem.Interval = newInterval
-	problem.NextReview = time.Now().AddDate(0, 0, newInterval)
+	return metadata, nil
+}
+
+// settingsFor returns the user's review settings, or the defaults when the
+// user never saved any. Other errors are returned, so a failed read never
+// schedules a review with the wrong algorithm.
+func (s *service) settingsFor(ctx context.Context, userID uuid.UUID) (*ReviewSettings, error) {
+	settings, err := s.repository.GetReviewSettings(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return DefaultReviewSettings(userID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// markSession records a review, or its undo, in the session of the day the
+// review was made, if the problem is part of it. It must run inside a
+// transaction, which holds the lock on the session row.
+func markSession(ctx context.Context, repo Repository, userID uuid.UUID, loc *time.Location, problemID uuid.UUID, at time.Time, done bool) error {
+	_, _, day := dayBounds(at, loc)
+
+	session, err := repo.GetSessionForUpdate(ctx, userID, day)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !containsID(session.ProblemIDs, problemID) {
+		return nil
+	}
+
+	completed := make([]uuid.UUID, 0, len(session.CompletedIDs)+1)
+	for _, id := range session.CompletedIDs {
+		if id != problemID {
+			completed = append(completed, id)
+		}
+	}
+	if done {
+		completed = append(completed, problemID)
+	}
+	session.CompletedIDs = completed
+
+	return repo.UpdateSession(ctx, session)
 }
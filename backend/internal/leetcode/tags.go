@@ -0,0 +1,193 @@
+package leetcode
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	maxTagsPerProblem = 20
+	maxTagNameLength  = 50
+)
+
+// canonicalTagNamespace derives the ids of the canonical tags, so every
+// database seeds them with the same ids.
+var canonicalTagNamespace = uuid.MustParse("5f8f2f0e-3c1a-4b8e-9a57-6c0de1b0c7a1")
+
+// CanonicalTag is the shared tag of a pattern.
+func CanonicalTag(p Pattern) Tag {
+	return Tag{
+		ID:   uuid.NewSHA1(canonicalTagNamespace, []byte(p)),
+		Name: string(p),
+	}
+}
+
+// normalizeTagName trims and collapses the spaces of a tag name and spells
+// pattern names the canonical way. It reports false for names that are
+// empty or too long.
+func normalizeTagName(name string) (string, bool) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" || utf8.RuneCountInString(name) > maxTagNameLength {
+		return "", false
+	}
+	if p, ok := ParsePattern(name); ok {
+		return string(p), true
+	}
+	return name, true
+}
+
+// normalizeTagNames normalizes names and drops the ones repeated ignoring
+// case, keeping the order of first appearance.
+func normalizeTagNames(names []string) ([]string, error) {
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name, ok := normalizeTagName(name)
+		if !ok {
+			return nil, ErrInvalidTags
+		}
+		key := strings.ToLower(name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, name)
+	}
+
+	if len(normalized) == 0 || len(normalized) > maxTagsPerProblem {
+		return nil, ErrInvalidTags
+	}
+	return normalized, nil
+}
+
+// tagResolver turns tag names into tags: pattern names into canonical tags
+// and other names into the user's own tags, created on first use. Resolved
+// tags are cached, so one resolver can serve a whole import.
+type tagResolver struct {
+	repo   Repository
+	userID uuid.UUID
+	cache  map[string]Tag
+}
+
+func newTagResolver(repo Repository, userID uuid.UUID) *tagResolver {
+	return &tagResolver{repo: repo, userID: userID, cache: make(map[string]Tag)}
+}
+
+// resolve expects names already passed through normalizeTagNames.
+func (r *tagResolver) resolve(ctx context.Context, names []string) ([]Tag, error) {
+	tags := make([]Tag, 0, len(names))
+	var missing []string
+	for _, name := range names {
+		if p, ok := ParsePattern(name); ok {
+			r.cache[strings.ToLower(name)] = CanonicalTag(p)
+		}
+		if _, ok := r.cache[strings.ToLower(name)]; !ok {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		found, err := r.repo.GetUserTagsByName(ctx, r.userID, missing)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range found {
+			r.cache[strings.ToLower(tag.Name)] = tag
+		}
+
+		for _, name := range missing {
+			if _, ok := r.cache[strings.ToLower(name)]; ok {
+				continue
+			}
+			userID := r.userID
+			tag := Tag{UserID: &userID, Name: name}
+			if err := r.repo.CreateTag(ctx, &tag); err != nil {
+				return nil, err
+			}
+			r.cache[strings.ToLower(name)] = tag
+		}
+	}
+
+	for _, name := range names {
+		tags = append(tags, r.cache[strings.ToLower(name)])
+	}
+	return tags, nil
+}
+
+func tagNames(tags []Tag) []string {
+	names := make([]string, len(tags))
+	for i := range tags {
+		names[i] = tags[i].Name
+	}
+	return names
+}
+
+// firstPattern returns the first tag that names a pattern, or "" when none
+// does.
+func firstPattern(tags []Tag) Pattern {
+	for i := range tags {
+		if p, ok := ParsePattern(tags[i].Name); ok {
+			return p
+		}
+	}
+	return ""
+}
+
+// withPattern puts the pattern sent by older clients in front of names.
+func withPattern(pattern *Pattern, names []string) ([]string, error) {
+	if pattern == nil {
+		return names, nil
+	}
+	p, ok := ParsePattern(string(*pattern))
+	if !ok {
+		return nil, ErrInvalidPattern
+	}
+	return append([]string{string(p)}, names...), nil
+}
+
+// sharesTag reports whether two problems have a tag in common.
+func sharesTag(a, b *LeetCodeProblem) bool {
+	for i := range a.Tags {
+		for j := range b.Tags {
+			if a.Tags[i].ID == b.Tags[j].ID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MigrateTags seeds the canonical tags and moves the single pattern that
+// problems had before tags into the tag relation, dropping the old column.
+// It runs after AutoMigrate and does nothing once the column is gone.
+func MigrateTags(db *gorm.DB) error {
+	canonical := make([]Tag, len(Patterns))
+	for i, p := range Patterns {
+		canonical[i] = CanonicalTag(p)
+	}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&canonical).Error; err != nil {
+		return err
+	}
+
+	if !db.Migrator().HasColumn(&LeetCodeProblem{}, "pattern") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`
+			INSERT INTO leetcode_problem_tags (problem_id, tag_id)
+			SELECT p.id, t.id
+			FROM leet_code_problems p
+			JOIN leetcode_tags t ON t.user_id IS NULL AND t.name = p.pattern
+			ON CONFLICT DO NOTHING`).Error
+		if err != nil {
+			return err
+		}
+		return tx.Migrator().DropColumn(&LeetCodeProblem{}, "pattern")
+	})
+}
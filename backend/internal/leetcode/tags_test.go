@@ -0,0 +1,39 @@
+package leetcode
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithPattern(t *testing.T) {
+	pattern := func(p Pattern) *Pattern { return &p }
+
+	tests := []struct {
+		pattern *Pattern
+		names   []string
+		want    []string
+		wantErr error
+	}{
+		{nil, []string{"Arrays"}, []string{"Arrays"}, nil},
+		{pattern("two pointers"), nil, []string{string(PatternTwoPointers)}, nil},
+		{pattern(PatternSlidingWindow), []string{"Arrays"}, []string{string(PatternSlidingWindow), "Arrays"}, nil},
+		{pattern("Recursion"), []string{"Arrays"}, nil, ErrInvalidPattern},
+	}
+
+	for _, tt := range tests {
+		got, err := withPattern(tt.pattern, tt.names)
+		if err != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("withPattern(%v, %v) = %v, %v, want %v, %v", tt.pattern, tt.names, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestFirstPattern(t *testing.T) {
+	tags := []Tag{{Name: "Arrays"}, CanonicalTag(PatternTwoPointers), CanonicalTag(PatternSlidingWindow)}
+	if got := firstPattern(tags); got != PatternTwoPointers {
+		t.Errorf("firstPattern = %q, want %q", got, PatternTwoPointers)
+	}
+	if got := firstPattern([]Tag{{Name: "Arrays"}}); got != "" {
+		t.Errorf("firstPattern without patterns = %q, want none", got)
+	}
+}
@@ -0,0 +1,107 @@
+package pomodoro
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UpdateSettingsDTO struct {
+	WorkMinutes       *int `json:"work_minutes,omitempty"`
+	ShortBreakMinutes *int `json:"short_break_minutes,omitempty"`
+	LongBreakMinutes  *int `json:"long_break_minutes,omitempty"`
+	LongBreakEvery    *int `json:"long_break_every,omitempty"`
+}
+
+type SettingsResponseDTO struct {
+	WorkMinutes       int `json:"work_minutes"`
+	ShortBreakMinutes int `json:"short_break_minutes"`
+	LongBreakMinutes  int `json:"long_break_minutes"`
+	LongBreakEvery    int `json:"long_break_every"`
+}
+
+// StartSessionDTO starts a new phase. Without a phase the server picks the
+// next one of the cycle (work, short break, ..., long break).
+type StartSessionDTO struct {
+	Phase        *Phase     `json:"phase,omitempty"`
+	TaskID       *uuid.UUID `json:"task_id,omitempty"`
+	CollectionID *uuid.UUID `json:"collection_id,omitempty"`
+}
+
+type SessionResponseDTO struct {
+	ID               uuid.UUID  `json:"id"`
+	Phase            Phase      `json:"phase"`
+	State            State      `json:"state"`
+	TaskID           *uuid.UUID `json:"task_id,omitempty"`
+	CollectionID     *uuid.UUID `json:"collection_id,omitempty"`
+	PlannedSeconds   int        `json:"planned_seconds"`
+	ElapsedSeconds   int        `json:"elapsed_seconds"`
+	RemainingSeconds int        `json:"remaining_seconds"`
+	FocusSeconds     int        `json:"focus_seconds"`
+	StartedAt        time.Time  `json:"started_at"`
+	PausedAt         *time.Time `json:"paused_at,omitempty"`
+	DueAt            *time.Time `json:"due_at,omitempty"`
+	EndedAt          *time.Time `json:"ended_at,omitempty"`
+}
+
+type FocusDayDTO struct {
+	Date         string `json:"date"`
+	FocusMinutes int    `json:"focus_minutes"`
+	Completed    int    `json:"completed"`
+}
+
+type FocusWeekDTO struct {
+	WeekStart    string `json:"week_start"`
+	FocusMinutes int    `json:"focus_minutes"`
+	Completed    int    `json:"completed"`
+}
+
+type StatsResponseDTO struct {
+	Timezone     string         `json:"timezone"`
+	TodayMinutes int            `json:"today_minutes"`
+	WeekMinutes  int            `json:"week_minutes"`
+	Daily        []FocusDayDTO  `json:"daily"`
+	Weekly       []FocusWeekDTO `json:"weekly"`
+}
+
+func ToSettingsResponse(s *Settings) SettingsResponseDTO {
+	return SettingsResponseDTO{
+		WorkMinutes:       s.WorkMinutes,
+		ShortBreakMinutes: s.ShortBreakMinutes,
+		LongBreakMinutes:  s.LongBreakMinutes,
+		LongBreakEvery:    s.LongBreakEvery,
+	}
+}
+
+func ToSessionResponse(s Session, now time.Time) SessionResponseDTO {
+	response := SessionResponseDTO{
+		ID:               s.ID,
+		Phase:            s.Phase,
+		State:            s.State,
+		TaskID:           s.TaskID,
+		CollectionID:     s.CollectionID,
+		PlannedSeconds:   s.PlannedSeconds,
+		ElapsedSeconds:   int(s.Elapsed(now).Seconds()),
+		RemainingSeconds: int(s.Remaining(now).Seconds()),
+		FocusSeconds:     s.FocusSeconds,
+		StartedAt:        s.StartedAt,
+		PausedAt:         s.PausedAt,
+		EndedAt:          s.EndedAt,
+	}
+	if s.State == StateRunning {
+		due := s.DueAt()
+		response.DueAt = &due
+	}
+	if !s.State.IsActive() {
+		response.RemainingSeconds = 0
+	}
+	return response
+}
+
+func ToSessionResponseList(sessions []Session, now time.Time) []SessionResponseDTO {
+	responses := make([]SessionResponseDTO, len(sessions))
+	for i, s := range sessions {
+		responses[i] = ToSessionResponse(s, now)
+	}
+	return responses
+}
@@ -0,0 +1,32 @@
+package pomodoro
+
+type Phase string
+
+const (
+	PhaseWork       Phase = "WORK"
+	PhaseShortBreak Phase = "SHORT_BREAK"
+	PhaseLongBreak  Phase = "LONG_BREAK"
+)
+
+func (p Phase) IsValid() bool {
+	switch p {
+	case PhaseWork, PhaseShortBreak, PhaseLongBreak:
+		return true
+	default:
+		return false
+	}
+}
+
+type State string
+
+const (
+	StateRunning   State = "RUNNING"
+	StatePaused    State = "PAUSED"
+	StateCompleted State = "COMPLETED"
+	StateAbandoned State = "ABANDONED"
+)
+
+// IsActive reports whether the session still holds the user's timer.
+func (s State) IsActive() bool {
+	return s == StateRunning || s == StatePaused
+}
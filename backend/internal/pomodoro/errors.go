@@ -0,0 +1,17 @@
+package pomodoro
+
+import "errors"
+
+var (
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrSessionNotFound    = errors.New("pomodoro session not found")
+	ErrSessionActive      = errors.New("a pomodoro session is already active")
+	ErrInvalidTransition  = errors.New("invalid session state transition")
+	ErrSessionNotFinished = errors.New("session time has not elapsed yet")
+	ErrInvalidPhase       = errors.New("invalid phase")
+	ErrInvalidSettings    = errors.New("durations must be between 1 and 180 minutes and long break interval between 1 and 12")
+	ErrTaskNotFound       = errors.New("task not found")
+	ErrCollectionNotFound = errors.New("collection not found")
+	ErrInvalidTimezone    = errors.New("invalid time zone")
+	ErrInvalidStatsRange  = errors.New("invalid statistics range")
+)
@@ -0,0 +1,213 @@
+package pomodoro
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos/internal/shared/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.service.GetSettings(r.Context())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, settings)
+}
+
+func (h *Handler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var dto UpdateSettingsDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_PAYLOAD", "Payload inválido")
+		return
+	}
+
+	settings, err := h.service.UpdateSettings(r.Context(), &dto)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, settings)
+}
+
+func (h *Handler) Start(w http.ResponseWriter, r *http.Request) {
+	var dto StartSessionDTO
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+			response.Error(w, http.StatusBadRequest, "INVALID_PAYLOAD", "Payload inválido")
+			return
+		}
+	}
+
+	session, err := h.service.Start(r.Context(), &dto)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, session)
+}
+
+func (h *Handler) GetCurrent(w http.ResponseWriter, r *http.Request) {
+	session, err := h.service.GetCurrent(r.Context())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, session)
+}
+
+func (h *Handler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "INVALID_LIMIT", "Parâmetro limit inválido")
+			return
+		}
+		limit = n
+	}
+
+	sessions, err := h.service.GetHistory(r.Context(), limit)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, sessions)
+}
+
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	session, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, session)
+}
+
+func (h *Handler) Pause(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.service.Pause)
+}
+
+func (h *Handler) Resume(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.service.Resume)
+}
+
+func (h *Handler) Abandon(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.service.Abandon)
+}
+
+func (h *Handler) Complete(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.service.Complete)
+}
+
+// GetStats serves GET /pomodoro/stats?tz=&days=&weeks=. Without tz the
+// stats follow the user's time zone.
+func (h *Handler) GetStats(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var loc *time.Location
+	if name := query.Get("tz"); name != "" {
+		var err error
+		if loc, err = time.LoadLocation(name); err != nil {
+			h.handleError(w, ErrInvalidTimezone)
+			return
+		}
+	}
+
+	days, err := intParam(query.Get("days"), 7)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_DAYS", "Parâmetro days inválido")
+		return
+	}
+	weeks, err := intParam(query.Get("weeks"), 4)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_WEEKS", "Parâmetro weeks inválido")
+		return
+	}
+
+	stats, err := h.service.GetStats(r.Context(), loc, days, weeks)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, stats)
+}
+
+func (h *Handler) transition(w http.ResponseWriter, r *http.Request, apply func(ctx context.Context, id uuid.UUID) (*SessionResponseDTO, error)) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	session, err := apply(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, session)
+}
+
+func (h *Handler) handleError(w http.ResponseWriter, err error) {
+	switch err {
+	case ErrUnauthorized:
+		response.Error(w, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+	case ErrSessionNotFound:
+		response.Error(w, http.StatusNotFound, "SESSION_NOT_FOUND", err.Error())
+	case ErrSessionActive:
+		response.Error(w, http.StatusConflict, "SESSION_ACTIVE", err.Error())
+	case ErrInvalidTransition:
+		response.Error(w, http.StatusConflict, "INVALID_TRANSITION", err.Error())
+	case ErrSessionNotFinished:
+		response.Error(w, http.StatusConflict, "SESSION_NOT_FINISHED", err.Error())
+	case ErrInvalidPhase:
+		response.Error(w, http.StatusBadRequest, "INVALID_PHASE", err.Error())
+	case ErrInvalidSettings:
+		response.Error(w, http.StatusBadRequest, "INVALID_SETTINGS", err.Error())
+	case ErrTaskNotFound:
+		response.Error(w, http.StatusNotFound, "TASK_NOT_FOUND", err.Error())
+	case ErrCollectionNotFound:
+		response.Error(w, http.StatusNotFound, "COLLECTION_NOT_FOUND", err.Error())
+	case ErrInvalidTimezone:
+		response.Error(w, http.StatusBadRequest, "INVALID_TIMEZONE", err.Error())
+	case ErrInvalidStatsRange:
+		response.Error(w, http.StatusBadRequest, "INVALID_RANGE", err.Error())
+	default:
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Erro interno no servidor")
+	}
+}
+
+func intParam(value string, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(value)
+}
@@ -0,0 +1,119 @@
+package pomodoro
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Settings holds the user's pomodoro lengths. Users without a row use
+// DefaultSettings.
+type Settings struct {
+	UserID            uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey"`
+	WorkMinutes       int       `json:"work_minutes" gorm:"not null"`
+	ShortBreakMinutes int       `json:"short_break_minutes" gorm:"not null"`
+	LongBreakMinutes  int       `json:"long_break_minutes" gorm:"not null"`
+	LongBreakEvery    int       `json:"long_break_every" gorm:"not null"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+func (Settings) TableName() string {
+	return "pomodoro_settings"
+}
+
+func DefaultSettings(userID uuid.UUID) *Settings {
+	return &Settings{
+		UserID:            userID,
+		WorkMinutes:       25,
+		ShortBreakMinutes: 5,
+		LongBreakMinutes:  15,
+		LongBreakEvery:    4,
+	}
+}
+
+// Duration returns the planned length of a phase.
+func (s *Settings) Duration(phase Phase) time.Duration {
+	switch phase {
+	case PhaseShortBreak:
+		return time.Duration(s.ShortBreakMinutes) * time.Minute
+	case PhaseLongBreak:
+		return time.Duration(s.LongBreakMinutes) * time.Minute
+	default:
+		return time.Duration(s.WorkMinutes) * time.Minute
+	}
+}
+
+// Session is a single pomodoro phase. The server keeps the clock: elapsed
+// time is derived from StartedAt, the accumulated pauses and, while paused,
+// PausedAt. The partial unique index keeps one active session per user.
+type Session struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey"`
+	UserID         uuid.UUID  `json:"user_id" gorm:"type:uuid;index;not null;uniqueIndex:idx_pomodoro_active,where:state IN ('RUNNING','PAUSED')"`
+	TaskID         *uuid.UUID `json:"task_id,omitempty" gorm:"type:uuid;index"`
+	CollectionID   *uuid.UUID `json:"collection_id,omitempty" gorm:"type:uuid;index"`
+	Phase          Phase      `json:"phase" gorm:"not null"`
+	State          State      `json:"state" gorm:"not null"`
+	PlannedSeconds int        `json:"planned_seconds" gorm:"not null"`
+	PausedSeconds  int        `json:"paused_seconds" gorm:"default:0"`
+	FocusSeconds   int        `json:"focus_seconds" gorm:"default:0"`
+	StartedAt      time.Time  `json:"started_at" gorm:"not null;index"`
+	PausedAt       *time.Time `json:"paused_at"`
+	EndedAt        *time.Time `json:"ended_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func (Session) TableName() string {
+	return "pomodoro_sessions"
+}
+
+func (s *Session) BeforeCreate(tx *gorm.DB) (err error) {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return
+}
+
+// Elapsed returns the time the session has actually been running.
+func (s *Session) Elapsed(now time.Time) time.Duration {
+	end := now
+	switch {
+	case s.EndedAt != nil:
+		end = *s.EndedAt
+	case s.PausedAt != nil:
+		end = *s.PausedAt
+	}
+
+	elapsed := end.Sub(s.StartedAt) - time.Duration(s.PausedSeconds)*time.Second
+	if elapsed < 0 {
+		return 0
+	}
+	return elapsed
+}
+
+func (s *Session) Planned() time.Duration {
+	return time.Duration(s.PlannedSeconds) * time.Second
+}
+
+// Remaining returns how much of the planned time is left.
+func (s *Session) Remaining(now time.Time) time.Duration {
+	remaining := s.Planned() - s.Elapsed(now)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// DueAt returns when a running session reaches its planned length.
+func (s *Session) DueAt() time.Time {
+	return s.StartedAt.Add(s.Planned() + time.Duration(s.PausedSeconds)*time.Second)
+}
+
+// FocusBucket is one row of the focus statistics.
+type FocusBucket struct {
+	Bucket    time.Time
+	Seconds   int64
+	Completed int
+}
@@ -0,0 +1,132 @@
+package pomodoro
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	GetSettings(ctx context.Context, userID uuid.UUID) (*Settings, error)
+	SaveSettings(ctx context.Context, settings *Settings) error
+
+	Create(ctx context.Context, session *Session) error
+	Update(ctx context.Context, session *Session) error
+	GetByID(ctx context.Context, id, userID uuid.UUID) (*Session, error)
+	GetActive(ctx context.Context, userID uuid.UUID) (*Session, error)
+	GetRecent(ctx context.Context, userID uuid.UUID, limit int) ([]Session, error)
+	FocusStats(ctx context.Context, userID uuid.UUID, unit string, loc *time.Location, from, to time.Time) ([]FocusBucket, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) GetSettings(ctx context.Context, userID uuid.UUID) (*Settings, error) {
+	var settings Settings
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		First(&settings).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+func (r *repository) SaveSettings(ctx context.Context, settings *Settings) error {
+	return r.db.WithContext(ctx).Save(settings).Error
+}
+
+func (r *repository) Create(ctx context.Context, session *Session) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+func (r *repository) Update(ctx context.Context, session *Session) error {
+	return r.db.WithContext(ctx).Save(session).Error
+}
+
+func (r *repository) GetByID(ctx context.Context, id, userID uuid.UUID) (*Session, error) {
+	var session Session
+
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&session).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (r *repository) GetActive(ctx context.Context, userID uuid.UUID) (*Session, error) {
+	var session Session
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND state IN ?", userID, []State{StateRunning, StatePaused}).
+		First(&session).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (r *repository) GetRecent(ctx context.Context, userID uuid.UUID, limit int) ([]Session, error) {
+	var sessions []Session
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("started_at DESC").
+		Limit(limit).
+		Find(&sessions).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// FocusStats sums the focused seconds of finished work sessions started in
+// [from, to), grouped by day or week in the given time zone.
+func (r *repository) FocusStats(ctx context.Context, userID uuid.UUID, unit string, loc *time.Location, from, to time.Time) ([]FocusBucket, error) {
+	var buckets []FocusBucket
+
+	err := r.db.WithContext(ctx).
+		Model(&Session{}).
+		Select(
+			"date_trunc(?, started_at AT TIME ZONE ?) AS bucket, SUM(focus_seconds) AS seconds, COUNT(*) FILTER (WHERE state = ?) AS completed",
+			unit, loc.String(), StateCompleted,
+		).
+		Where("user_id = ? AND phase = ? AND state IN ?", userID, PhaseWork, []State{StateCompleted, StateAbandoned}).
+		Where("started_at >= ? AND started_at < ?", from, to).
+		Group("bucket").
+		Order("bucket ASC").
+		Scan(&buckets).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buckets, nil
+}
+
+// isUniqueViolation reports whether err is Postgres rejecting a row that
+// breaks a unique index.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -0,0 +1,484 @@
+package pomodoro
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos/internal/auth"
+	"github.com/saulo-duarte/chronos/internal/collections"
+	"github.com/saulo-duarte/chronos/internal/shared/middlewares"
+	"github.com/saulo-duarte/chronos/internal/tasks"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	GetSettings(ctx context.Context) (*SettingsResponseDTO, error)
+	UpdateSettings(ctx context.Context, dto *UpdateSettingsDTO) (*SettingsResponseDTO, error)
+
+	Start(ctx context.Context, dto *StartSessionDTO) (*SessionResponseDTO, error)
+	GetCurrent(ctx context.Context) (*SessionResponseDTO, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*SessionResponseDTO, error)
+	GetHistory(ctx context.Context, limit int) ([]SessionResponseDTO, error)
+	Pause(ctx context.Context, id uuid.UUID) (*SessionResponseDTO, error)
+	Resume(ctx context.Context, id uuid.UUID) (*SessionResponseDTO, error)
+	Abandon(ctx context.Context, id uuid.UUID) (*SessionResponseDTO, error)
+	Complete(ctx context.Context, id uuid.UUID) (*SessionResponseDTO, error)
+
+	GetStats(ctx context.Context, loc *time.Location, days, weeks int) (*StatsResponseDTO, error)
+}
+
+const (
+	// completionTolerance absorbs the latency between the client timer
+	// reaching zero and the complete request arriving.
+	completionTolerance = 5 * time.Second
+	maxDurationMinutes  = 180
+	maxLongBreakEvery   = 12
+	defaultHistorySize  = 20
+	maxHistorySize      = 100
+	maxStatsDays        = 366
+	maxStatsWeeks       = 52
+)
+
+type service struct {
+	repository  Repository
+	tasks       tasks.Repository
+	collections collections.Repository
+	locations   auth.Locator
+}
+
+func NewService(repository Repository, taskRepository tasks.Repository, collectionRepository collections.Repository, locator auth.Locator) Service {
+	return &service{
+		repository:  repository,
+		tasks:       taskRepository,
+		collections: collectionRepository,
+		locations:   locator,
+	}
+}
+
+func (s *service) GetSettings(ctx context.Context) (*SettingsResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	settings, err := s.settingsFor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	response := ToSettingsResponse(settings)
+	return &response, nil
+}
+
+func (s *service) UpdateSettings(ctx context.Context, dto *UpdateSettingsDTO) (*SettingsResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	settings, err := s.settingsFor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if dto.WorkMinutes != nil {
+		settings.WorkMinutes = *dto.WorkMinutes
+	}
+	if dto.ShortBreakMinutes != nil {
+		settings.ShortBreakMinutes = *dto.ShortBreakMinutes
+	}
+	if dto.LongBreakMinutes != nil {
+		settings.LongBreakMinutes = *dto.LongBreakMinutes
+	}
+	if dto.LongBreakEvery != nil {
+		settings.LongBreakEvery = *dto.LongBreakEvery
+	}
+
+	for _, minutes := range []int{settings.WorkMinutes, settings.ShortBreakMinutes, settings.LongBreakMinutes} {
+		if minutes < 1 || minutes > maxDurationMinutes {
+			return nil, ErrInvalidSettings
+		}
+	}
+	if settings.LongBreakEvery < 1 || settings.LongBreakEvery > maxLongBreakEvery {
+		return nil, ErrInvalidSettings
+	}
+
+	if err := s.repository.SaveSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+
+	response := ToSettingsResponse(settings)
+	return &response, nil
+}
+
+// Start begins a new phase. A previous session whose time already ran out is
+// completed first; any other active session has to be finished explicitly.
+func (s *service) Start(ctx context.Context, dto *StartSessionDTO) (*SessionResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	now := time.Now()
+	active, err := s.repository.GetActive(ctx, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		if err := s.settle(ctx, active, now); err != nil {
+			return nil, err
+		}
+		if active.State.IsActive() {
+			return nil, ErrSessionActive
+		}
+	}
+
+	session := &Session{
+		UserID:       userID,
+		TaskID:       dto.TaskID,
+		CollectionID: dto.CollectionID,
+		State:        StateRunning,
+		StartedAt:    now,
+	}
+
+	if dto.TaskID != nil {
+		task, err := s.tasks.GetByID(ctx, *dto.TaskID, userID)
+		if err != nil {
+			return nil, ErrTaskNotFound
+		}
+		if session.CollectionID == nil {
+			session.CollectionID = task.CollectionID
+		}
+	}
+	if session.CollectionID != nil {
+		if _, err := s.collections.GetByID(ctx, *session.CollectionID, userID); err != nil {
+			return nil, ErrCollectionNotFound
+		}
+	}
+
+	settings, err := s.settingsFor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if dto.Phase != nil {
+		if !dto.Phase.IsValid() {
+			return nil, ErrInvalidPhase
+		}
+		session.Phase = *dto.Phase
+	} else {
+		session.Phase, err = s.nextPhase(ctx, userID, settings)
+		if err != nil {
+			return nil, err
+		}
+	}
+	session.PlannedSeconds = int(settings.Duration(session.Phase).Seconds())
+
+	// The check above can race with another start; idx_pomodoro_active then
+	// rejects the second session.
+	err = s.repository.Create(ctx, session)
+	if isUniqueViolation(err) {
+		return nil, ErrSessionActive
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response := ToSessionResponse(*session, now)
+	return &response, nil
+}
+
+// GetCurrent returns the user's active session, or nil when there is none.
+func (s *service) GetCurrent(ctx context.Context) (*SessionResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	session, err := s.repository.GetActive(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.settle(ctx, session, now); err != nil {
+		return nil, err
+	}
+	if !session.State.IsActive() {
+		return nil, nil
+	}
+
+	response := ToSessionResponse(*session, now)
+	return &response, nil
+}
+
+func (s *service) GetByID(ctx context.Context, id uuid.UUID) (*SessionResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	session, err := s.repository.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+
+	now := time.Now()
+	if err := s.settle(ctx, session, now); err != nil {
+		return nil, err
+	}
+
+	response := ToSessionResponse(*session, now)
+	return &response, nil
+}
+
+func (s *service) GetHistory(ctx context.Context, limit int) ([]SessionResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	if limit <= 0 {
+		limit = defaultHistorySize
+	}
+	if limit > maxHistorySize {
+		limit = maxHistorySize
+	}
+
+	sessions, err := s.repository.GetRecent(ctx, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToSessionResponseList(sessions, time.Now()), nil
+}
+
+func (s *service) Pause(ctx context.Context, id uuid.UUID) (*SessionResponseDTO, error) {
+	return s.changeState(ctx, id, func(session *Session, now time.Time) error {
+		if session.State != StateRunning {
+			return ErrInvalidTransition
+		}
+		session.PausedAt = &now
+		session.State = StatePaused
+		return nil
+	})
+}
+
+func (s *service) Resume(ctx context.Context, id uuid.UUID) (*SessionResponseDTO, error) {
+	return s.changeState(ctx, id, func(session *Session, now time.Time) error {
+		if session.State != StatePaused {
+			return ErrInvalidTransition
+		}
+		session.PausedSeconds += int(now.Sub(*session.PausedAt).Seconds())
+		session.PausedAt = nil
+		session.State = StateRunning
+		return nil
+	})
+}
+
+func (s *service) Abandon(ctx context.Context, id uuid.UUID) (*SessionResponseDTO, error) {
+	return s.changeState(ctx, id, func(session *Session, now time.Time) error {
+		if !session.State.IsActive() {
+			return ErrInvalidTransition
+		}
+		finish(session, StateAbandoned, now)
+		return nil
+	})
+}
+
+// Complete finishes the session once its planned time has elapsed. Sessions
+// cannot be completed early; stopping before the end is an abandon.
+func (s *service) Complete(ctx context.Context, id uuid.UUID) (*SessionResponseDTO, error) {
+	return s.changeState(ctx, id, func(session *Session, now time.Time) error {
+		if session.State == StateCompleted {
+			return nil
+		}
+		if !session.State.IsActive() {
+			return ErrInvalidTransition
+		}
+		if session.Remaining(now) > completionTolerance {
+			return ErrSessionNotFinished
+		}
+		finish(session, StateCompleted, now)
+		return nil
+	})
+}
+
+// GetStats reports focused minutes per day for the last days days and per
+// week (starting on monday) for the last weeks weeks, in the given zone or,
+// when loc is nil, in the user's time zone.
+func (s *service) GetStats(ctx context.Context, loc *time.Location, days, weeks int) (*StatsResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	if days < 1 || days > maxStatsDays || weeks < 1 || weeks > maxStatsWeeks {
+		return nil, ErrInvalidStatsRange
+	}
+
+	if loc == nil {
+		if loc, err = s.locations.Location(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	tomorrow := today.AddDate(0, 0, 1)
+	thisWeek := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+
+	dayFrom := today.AddDate(0, 0, -(days - 1))
+	dailyRows, err := s.repository.FocusStats(ctx, userID, "day", loc, dayFrom, tomorrow)
+	if err != nil {
+		return nil, err
+	}
+
+	weekFrom := thisWeek.AddDate(0, 0, -7*(weeks-1))
+	weeklyRows, err := s.repository.FocusStats(ctx, userID, "week", loc, weekFrom, tomorrow)
+	if err != nil {
+		return nil, err
+	}
+
+	daily := indexBuckets(dailyRows)
+	weekly := indexBuckets(weeklyRows)
+
+	stats := &StatsResponseDTO{
+		Timezone: loc.String(),
+		Daily:    make([]FocusDayDTO, 0, days),
+		Weekly:   make([]FocusWeekDTO, 0, weeks),
+	}
+
+	for day := dayFrom; day.Before(tomorrow); day = day.AddDate(0, 0, 1) {
+		key := day.Format("2006-01-02")
+		bucket := daily[key]
+		stats.Daily = append(stats.Daily, FocusDayDTO{
+			Date:         key,
+			FocusMinutes: int(bucket.Seconds / 60),
+			Completed:    bucket.Completed,
+		})
+	}
+
+	for week := weekFrom; !week.After(thisWeek); week = week.AddDate(0, 0, 7) {
+		key := week.Format("2006-01-02")
+		bucket := weekly[key]
+		stats.Weekly = append(stats.Weekly, FocusWeekDTO{
+			WeekStart:    key,
+			FocusMinutes: int(bucket.Seconds / 60),
+			Completed:    bucket.Completed,
+		})
+	}
+
+	stats.TodayMinutes = stats.Daily[len(stats.Daily)-1].FocusMinutes
+	stats.WeekMinutes = stats.Weekly[len(stats.Weekly)-1].FocusMinutes
+
+	return stats, nil
+}
+
+func (s *service) changeState(ctx context.Context, id uuid.UUID, apply func(session *Session, now time.Time) error) (*SessionResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	session, err := s.repository.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+
+	now := time.Now()
+	if err := s.settle(ctx, session, now); err != nil {
+		return nil, err
+	}
+
+	if err := apply(session, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.Update(ctx, session); err != nil {
+		return nil, err
+	}
+
+	response := ToSessionResponse(*session, now)
+	return &response, nil
+}
+
+// settle completes a running session whose planned time has run out, so
+// the stored state never lags behind the server clock.
+func (s *service) settle(ctx context.Context, session *Session, now time.Time) error {
+	if session.State != StateRunning || now.Before(session.DueAt()) {
+		return nil
+	}
+
+	finish(session, StateCompleted, session.DueAt())
+	return s.repository.Update(ctx, session)
+}
+
+// nextPhase continues the cycle: a break after each completed work session,
+// long every LongBreakEvery sessions, and work otherwise.
+func (s *service) nextPhase(ctx context.Context, userID uuid.UUID, settings *Settings) (Phase, error) {
+	recent, err := s.repository.GetRecent(ctx, userID, settings.LongBreakEvery*2)
+	if err != nil {
+		return "", err
+	}
+
+	if len(recent) == 0 || recent[0].Phase != PhaseWork || recent[0].State != StateCompleted {
+		return PhaseWork, nil
+	}
+
+	completed := 0
+	for _, session := range recent {
+		if session.Phase == PhaseLongBreak {
+			break
+		}
+		if session.Phase == PhaseWork && session.State == StateCompleted {
+			completed++
+		}
+	}
+
+	if completed%settings.LongBreakEvery == 0 {
+		return PhaseLongBreak, nil
+	}
+	return PhaseShortBreak, nil
+}
+
+// settingsFor returns the user's settings, or the defaults when the user
+// never saved any. Other errors are returned rather than hidden behind the
+// defaults.
+func (s *service) settingsFor(ctx context.Context, userID uuid.UUID) (*Settings, error) {
+	settings, err := s.repository.GetSettings(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return DefaultSettings(userID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func finish(session *Session, state State, at time.Time) {
+	if session.PausedAt != nil {
+		session.PausedSeconds += int(at.Sub(*session.PausedAt).Seconds())
+		session.PausedAt = nil
+	}
+	session.EndedAt = &at
+	session.State = state
+
+	if session.Phase == PhaseWork {
+		focus := session.Elapsed(at)
+		if focus > session.Planned() {
+			focus = session.Planned()
+		}
+		session.FocusSeconds = int(focus.Seconds())
+	}
+}
+
+func indexBuckets(rows []FocusBucket) map[string]FocusBucket {
+	buckets := make(map[string]FocusBucket, len(rows))
+	for _, row := range rows {
+		buckets[row.Bucket.Format("2006-01-02")] = row
+	}
+	return buckets
+}
@@ -1,6 +1,9 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strconv"
+)
 
 type Config struct {
 	DBURL              string
@@ -13,6 +16,14 @@ type Config struct {
 	StorageSecretKey   string
 	StorageBucketName  string
 	StorageAPIKey      string
+	// TaskTrashRetentionDays is how long deleted tasks stay in the trash
+	// before the purge job removes them for good.
+	TaskTrashRetentionDays int
+	// ReviewUndoWindowMinutes is how long after a LeetCode review it can
+	// still be undone.
+	ReviewUndoWindowMinutes int
+	// LeetCodeGraphQLURL is the GraphQL endpoint problems are imported from.
+	LeetCodeGraphQLURL string
 }
 
 func LoadConfig() *Config {
@@ -27,6 +38,10 @@ func LoadConfig() *Config {
 		StorageSecretKey:   getEnv("SUPABASE_API_SECRET", ""),
 		StorageBucketName:  getEnv("SUPABASE_STORAGE_BUCKET_NAME", "chronos"),
 		StorageAPIKey:      getEnv("SUPABASE_API_KEY", ""),
+
+		TaskTrashRetentionDays:  getEnvInt("TASK_TRASH_RETENTION_DAYS", 30),
+		ReviewUndoWindowMinutes: getEnvInt("REVIEW_UNDO_WINDOW_MINUTES", 10),
+		LeetCodeGraphQLURL:      getEnv("LEETCODE_GRAPHQL_URL", "https://leetcode.com/graphql"),
 	}
 }
 
@@ -36,3 +51,12 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getEnvInt(key string, fallback int) int {
+	if value, ok := os.LookupEnv(key); ok {
+		if n, err := strconv.Atoi(value); err == nil && n > 0 {
+			return n
+		}
+	}
+	return fallback
+}
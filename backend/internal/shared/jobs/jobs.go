@@ -0,0 +1,67 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Job is a unit of background work executed periodically, either by the
+// local ticker or by a scheduled event when running on Lambda.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+type Runner struct {
+	jobs []Job
+}
+
+func NewRunner(jobs ...Job) *Runner {
+	return &Runner{jobs: jobs}
+}
+
+// Run executes the named job, or every job when name is empty. Failures are
+// logged and the remaining jobs still run; the first error is returned.
+func (r *Runner) Run(ctx context.Context, name string) error {
+	var first error
+	for _, job := range r.jobs {
+		if name != "" && job.Name != name {
+			continue
+		}
+		if err := r.execute(ctx, job); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Start runs every job on its own ticker until ctx is cancelled.
+func (r *Runner) Start(ctx context.Context) {
+	for _, job := range r.jobs {
+		go func(job Job) {
+			ticker := time.NewTicker(job.Interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					r.execute(ctx, job)
+				}
+			}
+		}(job)
+	}
+}
+
+func (r *Runner) execute(ctx context.Context, job Job) error {
+	start := time.Now()
+	if err := job.Run(ctx); err != nil {
+		log.Printf("Job %s falhou: %v", job.Name, err)
+		return err
+	}
+	log.Printf("Job %s concluído em %s", job.Name, time.Since(start).Round(time.Millisecond))
+	return nil
+}
@@ -8,9 +8,14 @@ import (
 type APIResponse struct {
 	Success bool        `json:"success"`
 	Data    interface{} `json:"data,omitempty"`
+	Meta    *Meta       `json:"meta,omitempty"`
 	Error   *APIError   `json:"error,omitempty"`
 }
 
+type Meta struct {
+	NextCursor *string `json:"next_cursor"`
+}
+
 type APIError struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
@@ -25,6 +30,16 @@ func JSON(w http.ResponseWriter, status int, data interface{}) {
 	})
 }
 
+func JSONWithMeta(w http.ResponseWriter, status int, data interface{}, meta *Meta) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(APIResponse{
+		Success: true,
+		Data:    data,
+		Meta:    meta,
+	})
+}
+
 func Error(w http.ResponseWriter, status int, code string, message string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -7,12 +7,15 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/saulo-duarte/chronos/internal/auth"
+	"github.com/saulo-duarte/chronos/internal/calendar"
 	"github.com/saulo-duarte/chronos/internal/collections"
 	"github.com/saulo-duarte/chronos/internal/leetcode"
+	"github.com/saulo-duarte/chronos/internal/pomodoro"
 	"github.com/saulo-duarte/chronos/internal/resources"
 	sharedauth "github.com/saulo-duarte/chronos/internal/shared/auth"
 	"github.com/saulo-duarte/chronos/internal/shared/middlewares"
 	"github.com/saulo-duarte/chronos/internal/tasks"
+	"github.com/saulo-duarte/chronos/internal/templates"
 	"github.com/saulo-duarte/chronos/internal/objectives"
 )
 
@@ -24,6 +27,9 @@ type RouterConfig struct {
 	ResourceHandler   *resources.Handler
 	LeetCodeHandler   *leetcode.Handler
 	ObjectiveHandler  *objectives.Handler
+	CalendarHandler   *calendar.Handler
+	PomodoroHandler   *pomodoro.Handler
+	TemplateHandler   *templates.Handler
 	JWTService        *sharedauth.TokenService
 }
 
@@ -53,6 +59,7 @@ func New(cfg RouterConfig) *chi.Mux {
 			r.Group(func(r chi.Router) {
 				r.Use(middlewares.Auth(cfg.JWTService))
 				r.Get("/me", cfg.AuthHandler.Me)
+				r.Put("/me/timezone", cfg.AuthHandler.UpdateTimezone)
 			})
 		})
 
@@ -60,11 +67,48 @@ func New(cfg RouterConfig) *chi.Mux {
 			r.Use(middlewares.Auth(cfg.JWTService))
 			r.Post("/", cfg.TaskHandler.Create)
 			r.Get("/", cfg.TaskHandler.GetAll)
+			r.Post("/batch", cfg.TaskHandler.Batch)
+			r.Post("/quick-add", cfg.TaskHandler.QuickAdd)
+			r.Get("/occurrences", cfg.TaskHandler.GetOccurrences)
+			r.Get("/overdue", cfg.TaskHandler.GetOverdue)
+			r.Get("/rollover/settings", cfg.TaskHandler.GetRolloverSettings)
+			r.Put("/rollover/settings", cfg.TaskHandler.UpdateRolloverSettings)
+			r.Get("/rollover/logs", cfg.TaskHandler.GetRolloverLogs)
+			r.Get("/timer", cfg.TaskHandler.GetRunningTimer)
+			r.Get("/trash", cfg.TaskHandler.GetTrash)
 			r.Get("/{id}", cfg.TaskHandler.GetByID)
 			r.Patch("/{id}", cfg.TaskHandler.Update)
 			r.Patch("/{id}/status", cfg.TaskHandler.UpdateStatus)
+			r.Patch("/{id}/move", cfg.TaskHandler.Move)
 			r.Delete("/{id}", cfg.TaskHandler.Delete)
+			r.Post("/{id}/restore", cfg.TaskHandler.Restore)
+			r.Get("/{id}/subtasks", cfg.TaskHandler.GetSubtasks)
+			r.Post("/{id}/subtasks", cfg.TaskHandler.CreateSubtask)
+			r.Put("/{id}/subtasks/order", cfg.TaskHandler.ReorderSubtasks)
+			r.Post("/{id}/timer/start", cfg.TaskHandler.StartTimer)
+			r.Post("/{id}/timer/stop", cfg.TaskHandler.StopTimer)
+			r.Get("/{id}/time-entries", cfg.TaskHandler.GetTimeEntries)
+			r.Post("/{id}/time-entries", cfg.TaskHandler.CreateTimeEntry)
+			r.Delete("/{id}/time-entries/{entryID}", cfg.TaskHandler.DeleteTimeEntry)
+			r.Get("/{id}/comments", cfg.TaskHandler.GetComments)
+			r.Post("/{id}/comments", cfg.TaskHandler.CreateComment)
+			r.Patch("/{id}/comments/{commentID}", cfg.TaskHandler.UpdateComment)
+			r.Delete("/{id}/comments/{commentID}", cfg.TaskHandler.DeleteComment)
+			r.Get("/{id}/dependencies", cfg.TaskHandler.GetDependencies)
+			r.Post("/{id}/dependencies", cfg.TaskHandler.AddDependency)
+			r.Delete("/{id}/dependencies/{blockedByID}", cfg.TaskHandler.RemoveDependency)
 			r.Get("/collection/{collectionID}", cfg.TaskHandler.GetByCollection)
+			r.Get("/collection/{collectionID}/ordered", cfg.TaskHandler.GetOrderedByCollection)
+		})
+
+		r.Route("/templates", func(r chi.Router) {
+			r.Use(middlewares.Auth(cfg.JWTService))
+			r.Post("/", cfg.TemplateHandler.Create)
+			r.Get("/", cfg.TemplateHandler.GetAll)
+			r.Get("/{id}", cfg.TemplateHandler.GetByID)
+			r.Put("/{id}", cfg.TemplateHandler.Update)
+			r.Delete("/{id}", cfg.TemplateHandler.Delete)
+			r.Post("/{id}/instantiate", cfg.TemplateHandler.Instantiate)
 		})
 
 		r.Route("/collections", func(r chi.Router) {
@@ -74,6 +118,8 @@ func New(cfg RouterConfig) *chi.Mux {
 			r.Get("/{id}", cfg.CollectionHandler.GetByID)
 			r.Patch("/{id}", cfg.CollectionHandler.Update)
 			r.Delete("/{id}", cfg.CollectionHandler.Delete)
+			r.Get("/{id}/workflow", cfg.CollectionHandler.GetWorkflow)
+			r.Put("/{id}/workflow", cfg.CollectionHandler.UpdateWorkflow)
 		})
 
 		r.Route("/resources", func(r chi.Router) {
@@ -91,10 +137,22 @@ func New(cfg RouterConfig) *chi.Mux {
 			r.Post("/", cfg.LeetCodeHandler.Create)
 			r.Get("/", cfg.LeetCodeHandler.GetAll)
 			r.Get("/due", cfg.LeetCodeHandler.GetDue)
+			r.Get("/lookup", cfg.LeetCodeHandler.Lookup)
+			r.Post("/import", cfg.LeetCodeHandler.Import)
+			r.Post("/import/bulk", cfg.LeetCodeHandler.BulkImport)
+			r.Get("/session", cfg.LeetCodeHandler.GetSession)
+			r.Get("/trash", cfg.LeetCodeHandler.GetTrash)
+			r.Get("/tags", cfg.LeetCodeHandler.GetTags)
+			r.Get("/stats", cfg.LeetCodeHandler.GetStats)
+			r.Get("/settings", cfg.LeetCodeHandler.GetSettings)
+			r.Put("/settings", cfg.LeetCodeHandler.UpdateSettings)
 			r.Get("/{id}", cfg.LeetCodeHandler.GetByID)
 			r.Patch("/{id}", cfg.LeetCodeHandler.Update)
 			r.Post("/{id}/review", cfg.LeetCodeHandler.Review)
+			r.Post("/{id}/review/undo", cfg.LeetCodeHandler.UndoReview)
+			r.Get("/{id}/reviews", cfg.LeetCodeHandler.GetReviews)
 			r.Delete("/{id}", cfg.LeetCodeHandler.Delete)
+			r.Post("/{id}/restore", cfg.LeetCodeHandler.Restore)
 		})
 
 		r.Route("/objectives", func(r chi.Router) {
@@ -105,6 +163,33 @@ func New(cfg RouterConfig) *chi.Mux {
 			r.Patch("/{id}", cfg.ObjectiveHandler.Update)
 			r.Delete("/{id}", cfg.ObjectiveHandler.Delete)
 		})
+
+		r.Route("/calendar", func(r chi.Router) {
+			r.Get("/feed/{token}.ics", cfg.CalendarHandler.Feed)
+
+			r.Group(func(r chi.Router) {
+				r.Use(middlewares.Auth(cfg.JWTService))
+				r.Get("/", cfg.CalendarHandler.GetRange)
+				r.Get("/feed", cfg.CalendarHandler.GetFeed)
+				r.Post("/feed/rotate", cfg.CalendarHandler.RotateFeed)
+				r.Post("/import", cfg.CalendarHandler.Import)
+			})
+		})
+
+		r.Route("/pomodoro", func(r chi.Router) {
+			r.Use(middlewares.Auth(cfg.JWTService))
+			r.Get("/settings", cfg.PomodoroHandler.GetSettings)
+			r.Put("/settings", cfg.PomodoroHandler.UpdateSettings)
+			r.Get("/stats", cfg.PomodoroHandler.GetStats)
+			r.Post("/sessions", cfg.PomodoroHandler.Start)
+			r.Get("/sessions", cfg.PomodoroHandler.GetHistory)
+			r.Get("/sessions/current", cfg.PomodoroHandler.GetCurrent)
+			r.Get("/sessions/{id}", cfg.PomodoroHandler.GetByID)
+			r.Post("/sessions/{id}/pause", cfg.PomodoroHandler.Pause)
+			r.Post("/sessions/{id}/resume", cfg.PomodoroHandler.Resume)
+			r.Post("/sessions/{id}/abandon", cfg.PomodoroHandler.Abandon)
+			r.Post("/sessions/{id}/complete", cfg.PomodoroHandler.Complete)
+		})
 	})
 
 	return r
@@ -1,6 +1,8 @@
 package tasks
 
 import (
+	"github.com/saulo-duarte/chronos/internal/auth"
+	"github.com/saulo-duarte/chronos/internal/collections"
 	"gorm.io/gorm"
 )
 
@@ -10,9 +12,9 @@ type Container struct {
 	Handler    *Handler
 }
 
-func NewContainer(db *gorm.DB) *Container {
+func NewContainer(db *gorm.DB, collectionRepository collections.Repository, locator auth.Locator) *Container {
 	repo := NewRepository(db)
-	svc := NewService(repo)
+	svc := NewService(repo, collectionRepository, locator)
 	hdl := NewHandler(svc)
 
 	return &Container{
@@ -0,0 +1,102 @@
+package tasks
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// createsCycle reports whether adding "taskID is blocked by blockedByID" to
+// the existing edges would close a cycle, i.e. whether taskID can already be
+// reached from blockedByID by following blocked-by edges.
+func createsCycle(edges []DependencyEdge, taskID, blockedByID uuid.UUID) bool {
+	if taskID == blockedByID {
+		return true
+	}
+
+	blockers := make(map[uuid.UUID][]uuid.UUID, len(edges))
+	for _, e := range edges {
+		blockers[e.TaskID] = append(blockers[e.TaskID], e.BlockedByID)
+	}
+
+	visited := make(map[uuid.UUID]bool)
+	stack := []uuid.UUID{blockedByID}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if current == taskID {
+			return true
+		}
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+		stack = append(stack, blockers[current]...)
+	}
+
+	return false
+}
+
+// topologicalOrder sorts tasks so that every task comes after the tasks that
+// block it (Kahn's algorithm). Among tasks that are ready at the same time
+// the incoming order is kept, and edges to tasks outside the list are
+// ignored.
+func topologicalOrder(tasks []Task, edges []DependencyEdge) []Task {
+	index := make(map[uuid.UUID]int, len(tasks))
+	for i, t := range tasks {
+		index[t.ID] = i
+	}
+
+	indegree := make([]int, len(tasks))
+	dependents := make([][]int, len(tasks))
+	for _, e := range edges {
+		task, ok := index[e.TaskID]
+		if !ok {
+			continue
+		}
+		blocker, ok := index[e.BlockedByID]
+		if !ok {
+			continue
+		}
+		indegree[task]++
+		dependents[blocker] = append(dependents[blocker], task)
+	}
+
+	var ready []int
+	for i := range tasks {
+		if indegree[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	ordered := make([]Task, 0, len(tasks))
+	placed := make([]bool, len(tasks))
+	for len(ready) > 0 {
+		current := ready[0]
+		ready = ready[1:]
+
+		ordered = append(ordered, tasks[current])
+		placed[current] = true
+
+		for _, next := range dependents[current] {
+			indegree[next]--
+			if indegree[next] == 0 {
+				at := sort.SearchInts(ready, next)
+				ready = append(ready, 0)
+				copy(ready[at+1:], ready[at:])
+				ready[at] = next
+			}
+		}
+	}
+
+	// Cycles are rejected when dependencies are added, but keep any
+	// leftover task visible rather than dropping it.
+	for i, t := range tasks {
+		if !placed[i] {
+			ordered = append(ordered, t)
+		}
+	}
+
+	return ordered
+}
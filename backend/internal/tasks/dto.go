@@ -14,6 +14,10 @@ type CreateTaskDTO struct {
 	CollectionID *uuid.UUID `json:"collection_id,omitempty"`
 	StartTime    time.Time  `json:"start_time" validate:"required"`
 	EndTime      *time.Time `json:"end_time,omitempty"`
+	Recurrence   *string    `json:"recurrence_rule,omitempty"`
+	// ExternalUID is only set by the calendar import, to recognise events
+	// imported before; clients cannot send it.
+	ExternalUID *string `json:"-"`
 }
 
 type UpdateTaskDTO struct {
@@ -24,19 +28,183 @@ type UpdateTaskDTO struct {
 	CollectionID *uuid.UUID `json:"collection_id,omitempty"`
 	StartTime    *time.Time `json:"start_time" validate:"omitempty"`
 	EndTime      *time.Time `json:"end_time" validate:"omitempty"`
+	Recurrence   *string    `json:"recurrence_rule" validate:"omitempty"`
+}
+
+type CreateSubtaskDTO struct {
+	Title       string     `json:"title" validate:"required"`
+	Description *string    `json:"description,omitempty"`
+	Status      *Status    `json:"status,omitempty"`
+	Priority    *Priority  `json:"priority,omitempty"`
+	StartTime   *time.Time `json:"start_time,omitempty"`
+	EndTime     *time.Time `json:"end_time,omitempty"`
+}
+
+type ReorderSubtasksDTO struct {
+	IDs []uuid.UUID `json:"ids" validate:"required"`
+}
+
+// MoveTaskDTO places a task on the board: AfterID is the card right above
+// the new place and BeforeID the card right below it.
+type MoveTaskDTO struct {
+	Status   Status     `json:"status" validate:"required"`
+	AfterID  *uuid.UUID `json:"after_id,omitempty"`
+	BeforeID *uuid.UUID `json:"before_id,omitempty"`
+}
+
+type UpdateStatusDTO struct {
+	Status         Status     `json:"status" validate:"required"`
+	OccurrenceDate *time.Time `json:"occurrence_date,omitempty"`
 }
 
 type TaskResponseDTO struct {
-	ID           uuid.UUID  `json:"id"`
-	Title        string     `json:"title"`
-	Description  *string    `json:"description,omitempty"`
-	Status       Status     `json:"status"`
-	Priority     Priority   `json:"priority"`
-	CollectionID *uuid.UUID `json:"collection_id,omitempty"`
-	StartTime    time.Time  `json:"start_time"`
-	EndTime      *time.Time `json:"end_time,omitempty"`
-	FinishedAt   *time.Time `json:"finished_at,omitempty"`
-	CreatedAt    time.Time  `json:"created_at"`
+	ID             uuid.UUID   `json:"id"`
+	Title          string      `json:"title"`
+	Description    *string     `json:"description,omitempty"`
+	Status         Status      `json:"status"`
+	Priority       Priority    `json:"priority"`
+	CollectionID   *uuid.UUID  `json:"collection_id,omitempty"`
+	ParentID       *uuid.UUID  `json:"parent_id,omitempty"`
+	Position       int         `json:"position"`
+	BoardPosition  float64     `json:"board_position"`
+	Progress       *int        `json:"progress,omitempty"`
+	TrackedSeconds int64       `json:"tracked_seconds"`
+	BlockedBy      []uuid.UUID `json:"blocked_by,omitempty"`
+	IsBlocked      bool        `json:"is_blocked"`
+	IsOverdue      bool        `json:"is_overdue"`
+	StartTime      time.Time   `json:"start_time"`
+	EndTime        *time.Time  `json:"end_time,omitempty"`
+	Recurrence     *string     `json:"recurrence_rule,omitempty"`
+	FinishedAt     *time.Time  `json:"finished_at,omitempty"`
+	CreatedAt      time.Time   `json:"created_at"`
+	DeletedAt      *time.Time  `json:"deleted_at,omitempty"`
+}
+
+type CreateTimeEntryDTO struct {
+	StartedAt time.Time `json:"started_at" validate:"required"`
+	EndedAt   time.Time `json:"ended_at" validate:"required"`
+	Note      *string   `json:"note,omitempty"`
+}
+
+type TimeEntryResponseDTO struct {
+	ID              uuid.UUID  `json:"id"`
+	TaskID          uuid.UUID  `json:"task_id"`
+	StartedAt       time.Time  `json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at"`
+	DurationSeconds int64      `json:"duration_seconds"`
+	Running         bool       `json:"running"`
+	Manual          bool       `json:"manual"`
+	Note            *string    `json:"note,omitempty"`
+}
+
+// CommentDTO carries the markdown body of a comment, used both to create
+// and to edit it.
+type CommentDTO struct {
+	Body string `json:"body" validate:"required,max=10000"`
+}
+
+type CommentResponseDTO struct {
+	ID        uuid.UUID  `json:"id"`
+	TaskID    uuid.UUID  `json:"task_id"`
+	Body      string     `json:"body"`
+	Edited    bool       `json:"edited"`
+	EditedAt  *time.Time `json:"edited_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type AddDependencyDTO struct {
+	BlockedByID uuid.UUID `json:"blocked_by_id" validate:"required"`
+}
+
+// BatchRequestDTO applies one operation to many tasks. Only the parameter
+// of the chosen operation is read: status, priority, collection_id (null
+// removes the tasks from their collection) or days. With atomic set, a
+// single failing item rolls back the whole batch.
+type BatchRequestDTO struct {
+	IDs          []uuid.UUID    `json:"ids" validate:"required"`
+	Operation    BatchOperation `json:"operation" validate:"required"`
+	Status       *Status        `json:"status,omitempty"`
+	Priority     *Priority      `json:"priority,omitempty"`
+	CollectionID *uuid.UUID     `json:"collection_id,omitempty"`
+	Days         *int           `json:"days,omitempty"`
+	Atomic       bool           `json:"atomic"`
+}
+
+type BatchItemResultDTO struct {
+	ID      uuid.UUID        `json:"id"`
+	Success bool             `json:"success"`
+	Error   string           `json:"error,omitempty"`
+	Task    *TaskResponseDTO `json:"task,omitempty"`
+}
+
+type BatchResponseDTO struct {
+	Operation BatchOperation       `json:"operation"`
+	Committed bool                 `json:"committed"`
+	Succeeded int                  `json:"succeeded"`
+	Failed    int                  `json:"failed"`
+	Results   []BatchItemResultDTO `json:"results"`
+}
+
+// QuickAddDTO carries a free-text task such as "Revisar grafos amanhã às
+// 19h !alta #Algoritmos". Dates are resolved in Timezone, which defaults to
+// the user's time zone; without Commit only the parsed preview is returned.
+type QuickAddDTO struct {
+	Text     string `json:"text" validate:"required"`
+	Timezone string `json:"tz,omitempty"`
+	Commit   bool   `json:"commit"`
+}
+
+type QuickAddPreviewDTO struct {
+	Title           string     `json:"title"`
+	StartTime       time.Time  `json:"start_time"`
+	Priority        Priority   `json:"priority"`
+	CollectionID    *uuid.UUID `json:"collection_id,omitempty"`
+	CollectionTitle *string    `json:"collection_title,omitempty"`
+	Matched         []string   `json:"matched"`
+	Warnings        []string   `json:"warnings"`
+}
+
+type QuickAddResponseDTO struct {
+	Preview QuickAddPreviewDTO `json:"preview"`
+	Task    *TaskResponseDTO   `json:"task,omitempty"`
+}
+
+type UpdateRolloverSettingsDTO struct {
+	Policy RolloverPolicy `json:"policy" validate:"required"`
+}
+
+type RolloverSettingsResponseDTO struct {
+	Policy RolloverPolicy `json:"policy"`
+}
+
+type RolloverLogResponseDTO struct {
+	ID            uuid.UUID      `json:"id"`
+	TaskID        uuid.UUID      `json:"task_id"`
+	Policy        RolloverPolicy `json:"policy"`
+	PreviousStart time.Time      `json:"previous_start"`
+	PreviousEnd   *time.Time     `json:"previous_end,omitempty"`
+	NewStart      time.Time      `json:"new_start"`
+	NewEnd        *time.Time     `json:"new_end,omitempty"`
+	MovedAt       time.Time      `json:"moved_at"`
+}
+
+type TaskPageDTO struct {
+	Items      []TaskResponseDTO
+	NextCursor *string
+}
+
+type OccurrenceResponseDTO struct {
+	TaskID         uuid.UUID  `json:"task_id"`
+	Title          string     `json:"title"`
+	Status         Status     `json:"status"`
+	Priority       Priority   `json:"priority"`
+	CollectionID   *uuid.UUID `json:"collection_id,omitempty"`
+	OccurrenceDate time.Time  `json:"occurrence_date"`
+	StartTime      time.Time  `json:"start_time"`
+	EndTime        *time.Time `json:"end_time,omitempty"`
+	FinishedAt     *time.Time `json:"finished_at,omitempty"`
+	IsRecurring    bool       `json:"is_recurring"`
 }
 
 func (dto *CreateTaskDTO) ToEntity() *Task {
@@ -49,21 +217,58 @@ func (dto *CreateTaskDTO) ToEntity() *Task {
 		CollectionID: dto.CollectionID,
 		StartTime:    dto.StartTime,
 		EndTime:      dto.EndTime,
+		ExternalUID:  dto.ExternalUID,
+	}
+}
+
+func (dto *CreateSubtaskDTO) ToEntity(parent *Task) *Task {
+	task := &Task{
+		ID:           uuid.New(),
+		UserID:       parent.UserID,
+		Title:        dto.Title,
+		Description:  dto.Description,
+		Status:       Pending,
+		Priority:     parent.Priority,
+		CollectionID: parent.CollectionID,
+		ParentID:     &parent.ID,
+		StartTime:    parent.StartTime,
+		EndTime:      dto.EndTime,
+	}
+	if dto.Status != nil {
+		task.Status = *dto.Status
 	}
+	if dto.Priority != nil {
+		task.Priority = *dto.Priority
+	}
+	if dto.StartTime != nil {
+		task.StartTime = *dto.StartTime
+	}
+	return task
 }
 
 func ToResponse(t Task) TaskResponseDTO {
+	var deletedAt *time.Time
+	if t.DeletedAt.Valid {
+		deletedAt = &t.DeletedAt.Time
+	}
+
 	return TaskResponseDTO{
-		ID:           t.ID,
-		Title:        t.Title,
-		Description:  t.Description,
-		Status:       t.Status,
-		Priority:     t.Priority,
-		CollectionID: t.CollectionID,
-		StartTime:    t.StartTime,
-		EndTime:      t.EndTime,
-		FinishedAt:   t.FinishedAt,
-		CreatedAt:    t.CreatedAt,
+		ID:            t.ID,
+		Title:         t.Title,
+		Description:   t.Description,
+		Status:        t.Status,
+		Priority:      t.Priority,
+		CollectionID:  t.CollectionID,
+		ParentID:      t.ParentID,
+		Position:      t.Position,
+		BoardPosition: t.BoardPosition,
+		IsOverdue:     t.IsOverdue(time.Now()),
+		StartTime:     t.StartTime,
+		EndTime:       t.EndTime,
+		Recurrence:    t.RecurrenceRule,
+		FinishedAt:    t.FinishedAt,
+		CreatedAt:     t.CreatedAt,
+		DeletedAt:     deletedAt,
 	}
 }
 
@@ -74,3 +279,61 @@ func ToResponseList(tasks []Task) []TaskResponseDTO {
 	}
 	return responses
 }
+
+func ToTimeEntryResponse(e TimeEntry) TimeEntryResponseDTO {
+	return TimeEntryResponseDTO{
+		ID:              e.ID,
+		TaskID:          e.TaskID,
+		StartedAt:       e.StartedAt,
+		EndedAt:         e.EndedAt,
+		DurationSeconds: int64(e.Duration(time.Now()).Seconds()),
+		Running:         e.EndedAt == nil,
+		Manual:          e.Manual,
+		Note:            e.Note,
+	}
+}
+
+func ToTimeEntryResponseList(entries []TimeEntry) []TimeEntryResponseDTO {
+	responses := make([]TimeEntryResponseDTO, len(entries))
+	for i, e := range entries {
+		responses[i] = ToTimeEntryResponse(e)
+	}
+	return responses
+}
+
+func ToCommentResponse(c TaskComment) CommentResponseDTO {
+	return CommentResponseDTO{
+		ID:        c.ID,
+		TaskID:    c.TaskID,
+		Body:      c.Body,
+		Edited:    c.EditedAt != nil,
+		EditedAt:  c.EditedAt,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+func ToCommentResponseList(comments []TaskComment) []CommentResponseDTO {
+	responses := make([]CommentResponseDTO, len(comments))
+	for i, c := range comments {
+		responses[i] = ToCommentResponse(c)
+	}
+	return responses
+}
+
+func ToRolloverLogResponseList(logs []RolloverLog) []RolloverLogResponseDTO {
+	responses := make([]RolloverLogResponseDTO, len(logs))
+	for i, l := range logs {
+		responses[i] = RolloverLogResponseDTO{
+			ID:            l.ID,
+			TaskID:        l.TaskID,
+			Policy:        l.Policy,
+			PreviousStart: l.PreviousStart,
+			PreviousEnd:   l.PreviousEnd,
+			NewStart:      l.NewStart,
+			NewEnd:        l.NewEnd,
+			MovedAt:       l.CreatedAt,
+		}
+	}
+	return responses
+}
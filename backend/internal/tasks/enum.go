@@ -32,3 +32,40 @@ func (p Priority) IsValid() bool {
 		return false
 	}
 }
+
+type BatchOperation string
+
+const (
+	BatchSetStatus      BatchOperation = "SET_STATUS"
+	BatchSetPriority    BatchOperation = "SET_PRIORITY"
+	BatchMoveCollection BatchOperation = "MOVE_COLLECTION"
+	BatchShiftDates     BatchOperation = "SHIFT_DATES"
+	BatchDelete         BatchOperation = "DELETE"
+)
+
+func (o BatchOperation) IsValid() bool {
+	switch o {
+	case BatchSetStatus, BatchSetPriority, BatchMoveCollection, BatchShiftDates, BatchDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// RolloverPolicy decides what the rollover job does with overdue tasks.
+type RolloverPolicy string
+
+const (
+	RolloverLeave       RolloverPolicy = "LEAVE"
+	RolloverToday       RolloverPolicy = "TODAY"
+	RolloverNextFreeDay RolloverPolicy = "NEXT_FREE_DAY"
+)
+
+func (p RolloverPolicy) IsValid() bool {
+	switch p {
+	case RolloverLeave, RolloverToday, RolloverNextFreeDay:
+		return true
+	default:
+		return false
+	}
+}
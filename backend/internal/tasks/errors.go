@@ -8,4 +8,31 @@ var (
 	ErrInvalidTaskPriority  = errors.New("invalid task priority")
 	ErrTaskNotBelongsToUser = errors.New("task not belongs to user")
 	ErrUnauthorized         = errors.New("unauthorized")
+	ErrInvalidRecurrence    = errors.New("invalid recurrence rule")
+	ErrInvalidOccurrence    = errors.New("date is not an occurrence of this task")
+	ErrTaskNotRecurring     = errors.New("task is not recurring")
+	ErrInvalidDateRange     = errors.New("invalid date range")
+	ErrSubtaskRecurrence    = errors.New("subtasks cannot be recurring")
+	ErrInvalidSubtaskOrder  = errors.New("subtask order must list every subtask exactly once")
+	ErrInvalidCursor        = errors.New("invalid cursor")
+	ErrInvalidSortField     = errors.New("invalid sort field")
+	ErrInvalidPageSize      = errors.New("invalid page size")
+	ErrInvalidTransition    = errors.New("status transition not allowed by the collection workflow")
+	ErrInvalidBoardPosition = errors.New("neighbour tasks must be in the same board column")
+	ErrTimerRunning         = errors.New("a timer is already running")
+	ErrNoRunningTimer       = errors.New("no timer is running for this task")
+	ErrTimeEntryNotFound    = errors.New("time entry not found")
+	ErrInvalidTimeEntry     = errors.New("time entry must end after it starts and not in the future")
+	ErrParentDeleted        = errors.New("parent task is in the trash, restore it first")
+	ErrCommentNotFound      = errors.New("comment not found")
+	ErrInvalidComment       = errors.New("comment must have between 1 and 10000 characters")
+	ErrDependencyCycle      = errors.New("dependency would create a cycle")
+	ErrDependencyNotFound   = errors.New("dependency not found")
+	ErrTaskBlocked          = errors.New("task is blocked by unfinished tasks")
+	ErrInvalidBatch         = errors.New("batch needs 1 to 500 ids, a valid operation and its parameter")
+	ErrCollectionNotFound   = errors.New("collection not found")
+	ErrEmptyQuickAdd        = errors.New("quick-add text has no title")
+	ErrInvalidTimezone      = errors.New("invalid time zone")
+	ErrInvalidRollover      = errors.New("invalid rollover policy")
+	ErrAlreadyImported      = errors.New("an event with this UID was already imported")
 )
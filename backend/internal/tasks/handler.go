@@ -2,7 +2,11 @@ package tasks
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -33,8 +37,45 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusCreated, task)
 }
 
-func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
-	tasks, err := h.service.GetAllByUserID(r.Context())
+func (h *Handler) Batch(w http.ResponseWriter, r *http.Request) {
+	var dto BatchRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_PAYLOAD", "Payload inválido")
+		return
+	}
+
+	result, err := h.service.Batch(r.Context(), &dto)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, result)
+}
+
+func (h *Handler) QuickAdd(w http.ResponseWriter, r *http.Request) {
+	var dto QuickAddDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_PAYLOAD", "Payload inválido")
+		return
+	}
+
+	result, err := h.service.QuickAdd(r.Context(), &dto)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	status := http.StatusOK
+	if result.Task != nil {
+		status = http.StatusCreated
+	}
+
+	response.JSON(w, status, result)
+}
+
+func (h *Handler) GetOverdue(w http.ResponseWriter, r *http.Request) {
+	tasks, err := h.service.GetOverdue(r.Context())
 	if err != nil {
 		h.handleError(w, err)
 		return
@@ -43,6 +84,68 @@ func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusOK, tasks)
 }
 
+func (h *Handler) GetRolloverSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.service.GetRolloverSettings(r.Context())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, settings)
+}
+
+func (h *Handler) UpdateRolloverSettings(w http.ResponseWriter, r *http.Request) {
+	var dto UpdateRolloverSettingsDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_PAYLOAD", "Payload inválido")
+		return
+	}
+
+	settings, err := h.service.UpdateRolloverSettings(r.Context(), &dto)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, settings)
+}
+
+func (h *Handler) GetRolloverLogs(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "INVALID_LIMIT", "Parâmetro limit inválido")
+			return
+		}
+		limit = n
+	}
+
+	logs, err := h.service.GetRolloverLogs(r.Context(), limit)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, logs)
+}
+
+func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseTaskFilter(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_QUERY", err.Error())
+		return
+	}
+
+	page, err := h.service.List(r.Context(), *filter)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSONWithMeta(w, http.StatusOK, page.Items, &response.Meta{NextCursor: page.NextCursor})
+}
+
 func (h *Handler) GetByCollection(w http.ResponseWriter, r *http.Request) {
 	collectionID, err := uuid.Parse(chi.URLParam(r, "collectionID"))
 	if err != nil {
@@ -59,6 +162,44 @@ func (h *Handler) GetByCollection(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusOK, tasks)
 }
 
+func (h *Handler) GetOrderedByCollection(w http.ResponseWriter, r *http.Request) {
+	collectionID, err := uuid.Parse(chi.URLParam(r, "collectionID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_COLLECTION_ID", "ID de coleção inválido")
+		return
+	}
+
+	tasks, err := h.service.GetOrderedByCollection(r.Context(), collectionID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, tasks)
+}
+
+func (h *Handler) GetOccurrences(w http.ResponseWriter, r *http.Request) {
+	from, err := parseTimeParam(r.URL.Query().Get("from"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_FROM", "Parâmetro from inválido")
+		return
+	}
+
+	to, err := parseTimeParam(r.URL.Query().Get("to"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_TO", "Parâmetro to inválido")
+		return
+	}
+
+	occurrences, err := h.service.GetOccurrences(r.Context(), from, to, nil)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, occurrences)
+}
+
 func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
@@ -104,15 +245,24 @@ func (h *Handler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var payload struct {
-		Status Status `json:"status"`
-	}
-	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+	var dto UpdateStatusDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
 		response.Error(w, http.StatusBadRequest, "INVALID_PAYLOAD", "Status inválido")
 		return
 	}
 
-	task, err := h.service.UpdateStatus(r.Context(), id, payload.Status)
+	if dto.OccurrenceDate != nil {
+		occurrence, err := h.service.UpdateOccurrenceStatus(r.Context(), id, *dto.OccurrenceDate, dto.Status)
+		if err != nil {
+			h.handleError(w, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, occurrence)
+		return
+	}
+
+	task, err := h.service.UpdateStatus(r.Context(), id, dto.Status)
 	if err != nil {
 		h.handleError(w, err)
 		return
@@ -136,6 +286,361 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusOK, map[string]string{"message": "Tarefa excluída com sucesso"})
 }
 
+func (h *Handler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	tasks, err := h.service.GetTrash(r.Context())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, tasks)
+}
+
+func (h *Handler) Restore(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	task, err := h.service.Restore(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, task)
+}
+
+func (h *Handler) GetSubtasks(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	subtasks, err := h.service.GetSubtasks(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, subtasks)
+}
+
+func (h *Handler) CreateSubtask(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	var dto CreateSubtaskDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_PAYLOAD", "Payload inválido")
+		return
+	}
+
+	subtask, err := h.service.CreateSubtask(r.Context(), id, &dto)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, subtask)
+}
+
+func (h *Handler) ReorderSubtasks(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	var dto ReorderSubtasksDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_PAYLOAD", "Payload inválido")
+		return
+	}
+
+	subtasks, err := h.service.ReorderSubtasks(r.Context(), id, &dto)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, subtasks)
+}
+
+func (h *Handler) Move(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	var dto MoveTaskDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_PAYLOAD", "Payload inválido")
+		return
+	}
+
+	task, err := h.service.Move(r.Context(), id, &dto)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, task)
+}
+
+func (h *Handler) StartTimer(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	entry, err := h.service.StartTimer(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, entry)
+}
+
+func (h *Handler) StopTimer(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	entry, err := h.service.StopTimer(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, entry)
+}
+
+func (h *Handler) GetRunningTimer(w http.ResponseWriter, r *http.Request) {
+	entry, err := h.service.GetRunningTimer(r.Context())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, entry)
+}
+
+func (h *Handler) GetTimeEntries(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	entries, err := h.service.GetTimeEntries(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, entries)
+}
+
+func (h *Handler) CreateTimeEntry(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	var dto CreateTimeEntryDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_PAYLOAD", "Payload inválido")
+		return
+	}
+
+	entry, err := h.service.CreateTimeEntry(r.Context(), id, &dto)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, entry)
+}
+
+func (h *Handler) DeleteTimeEntry(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	entryID, err := uuid.Parse(chi.URLParam(r, "entryID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	if err := h.service.DeleteTimeEntry(r.Context(), id, entryID); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Registro de tempo excluído com sucesso"})
+}
+
+func (h *Handler) GetComments(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	comments, err := h.service.GetComments(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, comments)
+}
+
+func (h *Handler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	var dto CommentDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_PAYLOAD", "Payload inválido")
+		return
+	}
+
+	comment, err := h.service.CreateComment(r.Context(), id, &dto)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, comment)
+}
+
+func (h *Handler) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	commentID, err := uuid.Parse(chi.URLParam(r, "commentID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	var dto CommentDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_PAYLOAD", "Payload inválido")
+		return
+	}
+
+	comment, err := h.service.UpdateComment(r.Context(), id, commentID, &dto)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, comment)
+}
+
+func (h *Handler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	commentID, err := uuid.Parse(chi.URLParam(r, "commentID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	if err := h.service.DeleteComment(r.Context(), id, commentID); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Comentário excluído com sucesso"})
+}
+
+func (h *Handler) GetDependencies(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	blockers, err := h.service.GetDependencies(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, blockers)
+}
+
+func (h *Handler) AddDependency(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	var dto AddDependencyDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_PAYLOAD", "Payload inválido")
+		return
+	}
+
+	blockers, err := h.service.AddDependency(r.Context(), id, &dto)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, blockers)
+}
+
+func (h *Handler) RemoveDependency(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	blockedByID, err := uuid.Parse(chi.URLParam(r, "blockedByID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	if err := h.service.RemoveDependency(r.Context(), id, blockedByID); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Dependência removida com sucesso"})
+}
+
 func (h *Handler) handleError(w http.ResponseWriter, err error) {
 	switch err {
 	case ErrTaskNotFound:
@@ -148,7 +653,133 @@ func (h *Handler) handleError(w http.ResponseWriter, err error) {
 		response.Error(w, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
 	case ErrTaskNotBelongsToUser:
 		response.Error(w, http.StatusForbidden, "FORBIDDEN", err.Error())
+	case ErrInvalidRecurrence:
+		response.Error(w, http.StatusBadRequest, "INVALID_RECURRENCE", err.Error())
+	case ErrInvalidOccurrence:
+		response.Error(w, http.StatusBadRequest, "INVALID_OCCURRENCE", err.Error())
+	case ErrTaskNotRecurring:
+		response.Error(w, http.StatusBadRequest, "TASK_NOT_RECURRING", err.Error())
+	case ErrInvalidDateRange:
+		response.Error(w, http.StatusBadRequest, "INVALID_DATE_RANGE", err.Error())
+	case ErrSubtaskRecurrence:
+		response.Error(w, http.StatusBadRequest, "SUBTASK_RECURRENCE", err.Error())
+	case ErrInvalidSubtaskOrder:
+		response.Error(w, http.StatusBadRequest, "INVALID_SUBTASK_ORDER", err.Error())
+	case ErrInvalidCursor:
+		response.Error(w, http.StatusBadRequest, "INVALID_CURSOR", err.Error())
+	case ErrInvalidSortField:
+		response.Error(w, http.StatusBadRequest, "INVALID_SORT", err.Error())
+	case ErrInvalidPageSize:
+		response.Error(w, http.StatusBadRequest, "INVALID_LIMIT", err.Error())
+	case ErrInvalidTransition:
+		response.Error(w, http.StatusConflict, "INVALID_TRANSITION", err.Error())
+	case ErrInvalidBoardPosition:
+		response.Error(w, http.StatusBadRequest, "INVALID_BOARD_POSITION", err.Error())
+	case ErrTimerRunning:
+		response.Error(w, http.StatusConflict, "TIMER_RUNNING", err.Error())
+	case ErrNoRunningTimer:
+		response.Error(w, http.StatusConflict, "NO_RUNNING_TIMER", err.Error())
+	case ErrTimeEntryNotFound:
+		response.Error(w, http.StatusNotFound, "TIME_ENTRY_NOT_FOUND", err.Error())
+	case ErrParentDeleted:
+		response.Error(w, http.StatusConflict, "PARENT_DELETED", err.Error())
+	case ErrCommentNotFound:
+		response.Error(w, http.StatusNotFound, "COMMENT_NOT_FOUND", err.Error())
+	case ErrInvalidComment:
+		response.Error(w, http.StatusBadRequest, "INVALID_COMMENT", err.Error())
+	case ErrInvalidTimeEntry:
+		response.Error(w, http.StatusBadRequest, "INVALID_TIME_ENTRY", err.Error())
+	case ErrDependencyCycle:
+		response.Error(w, http.StatusConflict, "DEPENDENCY_CYCLE", err.Error())
+	case ErrDependencyNotFound:
+		response.Error(w, http.StatusNotFound, "DEPENDENCY_NOT_FOUND", err.Error())
+	case ErrTaskBlocked:
+		response.Error(w, http.StatusConflict, "TASK_BLOCKED", err.Error())
+	case ErrInvalidBatch:
+		response.Error(w, http.StatusBadRequest, "INVALID_BATCH", err.Error())
+	case ErrCollectionNotFound:
+		response.Error(w, http.StatusNotFound, "COLLECTION_NOT_FOUND", err.Error())
+	case ErrEmptyQuickAdd:
+		response.Error(w, http.StatusBadRequest, "EMPTY_QUICK_ADD", err.Error())
+	case ErrInvalidTimezone:
+		response.Error(w, http.StatusBadRequest, "INVALID_TIMEZONE", err.Error())
+	case ErrInvalidRollover:
+		response.Error(w, http.StatusBadRequest, "INVALID_ROLLOVER_POLICY", err.Error())
 	default:
 		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Erro interno no servidor")
 	}
 }
+
+// parseTaskFilter reads the listing query parameters: status, priority,
+// collection_id, from, to, q, sort, order, limit and cursor.
+func parseTaskFilter(r *http.Request) (*TaskFilter, error) {
+	query := r.URL.Query()
+	filter := &TaskFilter{
+		Search: strings.TrimSpace(query.Get("q")),
+		Sort:   SortField(query.Get("sort")),
+	}
+
+	if value := query.Get("status"); value != "" {
+		status := Status(strings.ToUpper(value))
+		filter.Status = &status
+	}
+	if value := query.Get("priority"); value != "" {
+		priority := Priority(strings.ToUpper(value))
+		filter.Priority = &priority
+	}
+	if value := query.Get("collection_id"); value != "" {
+		collectionID, err := uuid.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("collection_id inválido")
+		}
+		filter.CollectionID = &collectionID
+	}
+	if value := query.Get("from"); value != "" {
+		from, err := parseTimeParam(value)
+		if err != nil {
+			return nil, fmt.Errorf("from inválido")
+		}
+		filter.From = &from
+	}
+	if value := query.Get("to"); value != "" {
+		to, err := parseTimeParam(value)
+		if err != nil {
+			return nil, fmt.Errorf("to inválido")
+		}
+		filter.To = &to
+	}
+
+	switch strings.ToLower(query.Get("order")) {
+	case "", "asc":
+	case "desc":
+		filter.Descending = true
+	default:
+		return nil, fmt.Errorf("order deve ser asc ou desc")
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("limit inválido")
+		}
+		filter.Limit = limit
+	}
+	if value := query.Get("cursor"); value != "" {
+		cursor, err := DecodeCursor(value)
+		if err != nil {
+			return nil, fmt.Errorf("cursor inválido")
+		}
+		filter.Cursor = cursor
+	}
+
+	return filter, nil
+}
+
+// parseTimeParam accepts either a full RFC 3339 timestamp or a plain date
+// (interpreted as midnight UTC).
+func parseTimeParam(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
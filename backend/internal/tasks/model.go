@@ -9,20 +9,28 @@ import (
 
 type Task struct {
 	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey"`
-	UserID       uuid.UUID  `json:"user_id" gorm:"type:uuid;index;not null"`
+	UserID       uuid.UUID  `json:"user_id" gorm:"type:uuid;index;not null;uniqueIndex:idx_tasks_external_uid_user,where:external_uid IS NOT NULL"`
 	Title        string     `json:"title" gorm:"column:name;not null"`
 	Description  *string    `json:"description,omitempty"`
 	Status       Status     `json:"status" gorm:"not null"`
 	Priority     Priority   `json:"priority" gorm:"not null"`
 	CollectionID *uuid.UUID `json:"collection_id,omitempty" gorm:"type:uuid;index"`
+	ParentID     *uuid.UUID `json:"parent_id,omitempty" gorm:"type:uuid;index"`
+	Position     int        `json:"position" gorm:"default:0"`
+	// BoardPosition orders tasks inside their kanban column. New positions
+	// are taken between neighbours so a drag only updates the moved task.
+	BoardPosition float64 `json:"board_position" gorm:"default:0;index"`
+
+	RecurrenceRule *string `json:"recurrence_rule,omitempty"`
+	ExternalUID    *string `json:"external_uid,omitempty" gorm:"uniqueIndex:idx_tasks_external_uid_user,where:external_uid IS NOT NULL"`
 
 	StartTime  time.Time  `json:"start_time"`
 	EndTime    *time.Time `json:"end_time"`
 	FinishedAt *time.Time `json:"finished_at"`
 
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"`
 }
 
 func (t *Task) BeforeCreate(tx *gorm.DB) (err error) {
@@ -31,3 +39,149 @@ func (t *Task) BeforeCreate(tx *gorm.DB) (err error) {
 	}
 	return
 }
+
+// duration is how long each occurrence of the task lasts.
+func (t *Task) duration() time.Duration {
+	if t.EndTime == nil {
+		return 0
+	}
+	return t.EndTime.Sub(t.StartTime)
+}
+
+// IsOverdue reports whether the task's EndTime has passed while it is still
+// unfinished. Recurring tasks are never overdue as a whole.
+func (t *Task) IsOverdue(now time.Time) bool {
+	return t.FinishedAt == nil && t.RecurrenceRule == nil && t.EndTime != nil && t.EndTime.Before(now)
+}
+
+// SubtaskCount summarizes the direct children of a task.
+type SubtaskCount struct {
+	ParentID uuid.UUID
+	Total    int
+	Done     int
+}
+
+// TaskOccurrence stores the state of a single occurrence of a recurring task.
+// Occurrences without a row inherit the state of the series.
+type TaskOccurrence struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey"`
+	TaskID         uuid.UUID  `json:"task_id" gorm:"type:uuid;not null;uniqueIndex:idx_task_occurrence"`
+	UserID         uuid.UUID  `json:"user_id" gorm:"type:uuid;index;not null"`
+	OccurrenceDate time.Time  `json:"occurrence_date" gorm:"not null;uniqueIndex:idx_task_occurrence"`
+	Status         Status     `json:"status" gorm:"not null"`
+	FinishedAt     *time.Time `json:"finished_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (o *TaskOccurrence) BeforeCreate(tx *gorm.DB) (err error) {
+	if o.ID == uuid.Nil {
+		o.ID = uuid.New()
+	}
+	return
+}
+
+// TimeEntry is one tracked work session on a task. Entries started by the
+// timer stay open (EndedAt nil) until stopped; the partial unique index keeps
+// at most one running timer per user.
+type TimeEntry struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey"`
+	TaskID    uuid.UUID  `json:"task_id" gorm:"type:uuid;index;not null"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;index;not null;uniqueIndex:idx_time_entries_running,where:ended_at IS NULL"`
+	StartedAt time.Time  `json:"started_at" gorm:"not null"`
+	EndedAt   *time.Time `json:"ended_at"`
+	Manual    bool       `json:"manual" gorm:"default:false"`
+	Note      *string    `json:"note,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (e *TimeEntry) BeforeCreate(tx *gorm.DB) (err error) {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return
+}
+
+// Duration returns the tracked time of the entry, counting a running timer
+// up to now.
+func (e *TimeEntry) Duration(now time.Time) time.Duration {
+	if e.EndedAt == nil {
+		return now.Sub(e.StartedAt)
+	}
+	return e.EndedAt.Sub(e.StartedAt)
+}
+
+// TaskComment is a markdown note left on a task. Comments keep the history
+// of a task that would otherwise be lost by overwriting its description.
+type TaskComment struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey"`
+	TaskID    uuid.UUID  `json:"task_id" gorm:"type:uuid;index;not null"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;index;not null"`
+	Body      string     `json:"body" gorm:"type:text;not null"`
+	EditedAt  *time.Time `json:"edited_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func (c *TaskComment) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return
+}
+
+// TaskDependency records that TaskID cannot be finished before BlockedByID.
+type TaskDependency struct {
+	TaskID      uuid.UUID `json:"task_id" gorm:"type:uuid;primaryKey"`
+	BlockedByID uuid.UUID `json:"blocked_by_id" gorm:"type:uuid;primaryKey;index"`
+	UserID      uuid.UUID `json:"user_id" gorm:"type:uuid;index;not null"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// DependencyEdge is a dependency together with whether its blocker is
+// still unfinished.
+type DependencyEdge struct {
+	TaskID      uuid.UUID
+	BlockedByID uuid.UUID
+	Pending     bool
+}
+
+// RolloverSettings is the user's policy for overdue tasks. Users without a
+// row keep their overdue tasks where they are.
+type RolloverSettings struct {
+	UserID    uuid.UUID      `json:"user_id" gorm:"type:uuid;primaryKey"`
+	Policy    RolloverPolicy `json:"policy" gorm:"not null"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+func (RolloverSettings) TableName() string {
+	return "task_rollover_settings"
+}
+
+// RolloverLog records a task moved by the rollover job.
+type RolloverLog struct {
+	ID            uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey"`
+	UserID        uuid.UUID      `json:"user_id" gorm:"type:uuid;index;not null"`
+	TaskID        uuid.UUID      `json:"task_id" gorm:"type:uuid;index;not null"`
+	Policy        RolloverPolicy `json:"policy" gorm:"not null"`
+	PreviousStart time.Time      `json:"previous_start"`
+	PreviousEnd   *time.Time     `json:"previous_end"`
+	NewStart      time.Time      `json:"new_start"`
+	NewEnd        *time.Time     `json:"new_end"`
+	CreatedAt     time.Time      `json:"created_at" gorm:"index"`
+}
+
+func (RolloverLog) TableName() string {
+	return "task_rollover_logs"
+}
+
+func (l *RolloverLog) BeforeCreate(tx *gorm.DB) (err error) {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,119 @@
+package tasks
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+type SortField string
+
+const (
+	SortByStartTime SortField = "start_time"
+	SortByCreatedAt SortField = "created_at"
+	SortByTitle     SortField = "title"
+)
+
+func (f SortField) IsValid() bool {
+	switch f {
+	case SortByStartTime, SortByCreatedAt, SortByTitle:
+		return true
+	default:
+		return false
+	}
+}
+
+func (f SortField) column() string {
+	if f == SortByTitle {
+		return "name"
+	}
+	return string(f)
+}
+
+// TaskFilter describes a filtered, sorted and keyset-paginated task listing.
+// Listings are paged only when Limit or Cursor is set; a zero Limit with a
+// Cursor means defaultPageSize, and without one every task is returned.
+type TaskFilter struct {
+	Status       *Status
+	Priority     *Priority
+	CollectionID *uuid.UUID
+	From         *time.Time
+	To           *time.Time
+	Search       string
+	Sort         SortField
+	Descending   bool
+	Limit        int
+	Cursor       *Cursor
+}
+
+// Cursor points at the last task of a page: the value of the sort column and
+// the task id used as tie-breaker. It records the sort it was made for, so it
+// cannot be used to page through a listing sorted another way.
+type Cursor struct {
+	Sort       SortField `json:"s"`
+	Descending bool      `json:"d,omitempty"`
+	Value      string    `json:"v"`
+	ID         uuid.UUID `json:"id"`
+}
+
+func NewCursor(t Task, sort SortField, descending bool) Cursor {
+	cursor := Cursor{Sort: sort, Descending: descending, ID: t.ID}
+	switch sort {
+	case SortByTitle:
+		cursor.Value = t.Title
+	case SortByCreatedAt:
+		cursor.Value = t.CreatedAt.Format(time.RFC3339Nano)
+	default:
+		cursor.Value = t.StartTime.Format(time.RFC3339Nano)
+	}
+	return cursor
+}
+
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeCursor(encoded string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.ID == uuid.Nil || !cursor.Sort.IsValid() {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+// matches reports whether the cursor was made for the given sort.
+func (c Cursor) matches(sort SortField, descending bool) bool {
+	return c.Sort == sort && c.Descending == descending
+}
+
+// sortValue converts the cursor value back to the type of its sort column.
+func (c Cursor) sortValue() (interface{}, error) {
+	if c.Sort == SortByTitle {
+		return c.Value, nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, c.Value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return t, nil
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
@@ -0,0 +1,306 @@
+package tasks
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultQuickAddHour is used when the text names a day but no time.
+const defaultQuickAddHour = 9
+
+// QuickAdd is the result of parsing a quick-add sentence such as
+// "Review graphs tomorrow at 19h !high #Algorithms".
+type QuickAdd struct {
+	Title      string
+	Priority   Priority
+	Collection string
+	StartTime  time.Time
+	Matched    []string
+}
+
+var (
+	timePattern      = regexp.MustCompile(`^(\d{1,2})(?:(?::|h)(\d{2})?)?(am|pm)?$`)
+	shortDatePattern = regexp.MustCompile(`^(\d{1,2})/(\d{1,2})(?:/(\d{2}|\d{4}))?$`)
+	isoDatePattern   = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+)
+
+var quickAddPriorities = map[string]Priority{
+	"high": High, "h": High, "alta": High, "urgente": High, "urgent": High,
+	"medium": Medium, "m": Medium, "media": Medium, "normal": Medium,
+	"low": Low, "l": Low, "baixa": Low,
+}
+
+var quickAddWeekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "domingo": time.Sunday,
+	"monday": time.Monday, "segunda": time.Monday, "segunda-feira": time.Monday,
+	"tuesday": time.Tuesday, "terca": time.Tuesday, "terca-feira": time.Tuesday,
+	"wednesday": time.Wednesday, "quarta": time.Wednesday, "quarta-feira": time.Wednesday,
+	"thursday": time.Thursday, "quinta": time.Thursday, "quinta-feira": time.Thursday,
+	"friday": time.Friday, "sexta": time.Friday, "sexta-feira": time.Friday,
+	"saturday": time.Saturday, "sabado": time.Saturday,
+}
+
+// quickAddDays maps fixed phrases (already lowercased and without accents)
+// to a day offset from today.
+var quickAddDays = []struct {
+	words  []string
+	offset int
+}{
+	{[]string{"day", "after", "tomorrow"}, 2},
+	{[]string{"depois", "de", "amanha"}, 2},
+	{[]string{"today"}, 0},
+	{[]string{"hoje"}, 0},
+	{[]string{"tomorrow"}, 1},
+	{[]string{"amanha"}, 1},
+}
+
+var weekdayPrefixes = map[string]bool{
+	"next": true, "on": true, "this": true,
+	"proxima": true, "proximo": true, "na": true, "no": true, "nesta": true, "neste": true,
+}
+
+var timePrefixes = map[string]bool{"at": true, "as": true, "@": true}
+
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a",
+	"é", "e", "ê", "e",
+	"í", "i",
+	"ó", "o", "ô", "o", "õ", "o",
+	"ú", "u", "ü", "u",
+	"ç", "c",
+)
+
+// ParseQuickAdd extracts the priority (!high, !alta), collection (#Name,
+// with underscores for spaces), day and time from the text; the remaining
+// words form the title. Day and time phrases are understood in English and
+// Portuguese and resolved relative to now, whose location is used for the
+// resulting StartTime.
+//
+// A bare hour such as "2h" may as well be a duration ("Estudar grafos 2h"),
+// so it is only read as a time after "at"/"às"; otherwise it stays in the
+// title. "2h30", "19:30" and "7pm" are always times.
+func ParseQuickAdd(text string, now time.Time) (*QuickAdd, error) {
+	words := strings.Fields(text)
+	normalized := make([]string, len(words))
+	for i, w := range words {
+		normalized[i] = accentReplacer.Replace(strings.ToLower(w))
+	}
+
+	result := &QuickAdd{Priority: Medium}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	var day *time.Time
+	hour, minute := -1, 0
+	var title []string
+
+	for i := 0; i < len(words); {
+		word := normalized[i]
+
+		if strings.HasPrefix(word, "!") {
+			if priority, ok := quickAddPriorities[strings.TrimPrefix(word, "!")]; ok {
+				result.Priority = priority
+				result.Matched = append(result.Matched, words[i])
+				i++
+				continue
+			}
+		}
+
+		if strings.HasPrefix(words[i], "#") && len(words[i]) > 1 {
+			result.Collection = strings.ReplaceAll(words[i][1:], "_", " ")
+			result.Matched = append(result.Matched, words[i])
+			i++
+			continue
+		}
+
+		if d, n := matchDay(normalized[i:], today); n > 0 {
+			day = &d
+			result.Matched = append(result.Matched, strings.Join(words[i:i+n], " "))
+			i += n
+			continue
+		}
+
+		if h, m, n := matchTime(normalized[i:]); n > 0 && !(n == 1 && isHourOnly(normalized[i])) {
+			hour, minute = h, m
+			result.Matched = append(result.Matched, strings.Join(words[i:i+n], " "))
+			i += n
+			continue
+		}
+
+		title = append(title, words[i])
+		i++
+	}
+
+	result.Title = strings.TrimSpace(strings.Join(title, " "))
+	if result.Title == "" {
+		return nil, ErrEmptyQuickAdd
+	}
+
+	if day == nil && hour < 0 {
+		result.StartTime = now.Truncate(time.Minute)
+		return result, nil
+	}
+
+	if day == nil {
+		day = &today
+	}
+	if hour < 0 {
+		hour = defaultQuickAddHour
+	}
+	result.StartTime = time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, now.Location())
+
+	return result, nil
+}
+
+// matchDay recognizes a day phrase at the start of words and returns the
+// day together with the number of words consumed.
+func matchDay(words []string, today time.Time) (time.Time, int) {
+	for _, phrase := range quickAddDays {
+		if hasPrefix(words, phrase.words) {
+			return today.AddDate(0, 0, phrase.offset), len(phrase.words)
+		}
+	}
+
+	// "in 3 days", "em 2 semanas", "daqui a 3 dias"
+	start := 0
+	switch {
+	case len(words) > 0 && (words[0] == "in" || words[0] == "em"):
+		start = 1
+	case hasPrefix(words, []string{"daqui", "a"}):
+		start = 2
+	}
+	if start > 0 && len(words) >= start+2 {
+		if n, err := strconv.Atoi(words[start]); err == nil && n > 0 && n <= 366 {
+			switch words[start+1] {
+			case "day", "days", "dia", "dias":
+				return today.AddDate(0, 0, n), start + 2
+			case "week", "weeks", "semana", "semanas":
+				return today.AddDate(0, 0, 7*n), start + 2
+			}
+		}
+	}
+
+	// "next week", "próxima semana", "semana que vem": next monday
+	if hasPrefix(words, []string{"next", "week"}) || hasPrefix(words, []string{"proxima", "semana"}) {
+		return nextWeekday(today, time.Monday), 2
+	}
+	if hasPrefix(words, []string{"semana", "que", "vem"}) {
+		return nextWeekday(today, time.Monday), 3
+	}
+
+	// "friday", "next friday", "na sexta", "sexta-feira"
+	offset := 0
+	if len(words) > 1 && weekdayPrefixes[words[0]] {
+		offset = 1
+	}
+	if len(words) > offset {
+		if weekday, ok := quickAddWeekdays[strings.TrimSuffix(words[offset], ",")]; ok {
+			return nextWeekday(today, weekday), offset + 1
+		}
+	}
+
+	if len(words) == 0 {
+		return time.Time{}, 0
+	}
+
+	if isoDatePattern.MatchString(words[0]) {
+		if d, err := time.ParseInLocation("2006-01-02", words[0], today.Location()); err == nil {
+			return d, 1
+		}
+	}
+
+	// "25/12" or "25/12/2026", day first as used in Brazil.
+	if m := shortDatePattern.FindStringSubmatch(words[0]); m != nil {
+		dayOfMonth, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		year := today.Year()
+		if m[3] != "" {
+			year, _ = strconv.Atoi(m[3])
+			if year < 100 {
+				year += 2000
+			}
+		}
+		d := time.Date(year, time.Month(month), dayOfMonth, 0, 0, 0, 0, today.Location())
+		if d.Day() != dayOfMonth || int(d.Month()) != month {
+			return time.Time{}, 0
+		}
+		if m[3] == "" && d.Before(today) {
+			d = d.AddDate(1, 0, 0)
+		}
+		return d, 1
+	}
+
+	return time.Time{}, 0
+}
+
+// matchTime recognizes "19h", "19h30", "19:30", "7pm", "noon" or
+// "meio-dia", optionally preceded by "at" or "às".
+func matchTime(words []string) (hour, minute, consumed int) {
+	offset := 0
+	if len(words) > 1 && timePrefixes[words[0]] {
+		offset = 1
+	}
+	if len(words) <= offset {
+		return 0, 0, 0
+	}
+
+	word := words[offset]
+	if word == "noon" || word == "meio-dia" {
+		return 12, 0, offset + 1
+	}
+
+	m := timePattern.FindStringSubmatch(word)
+	if m == nil || (!strings.ContainsAny(word, ":h") && m[3] == "") {
+		return 0, 0, 0
+	}
+
+	hour, _ = strconv.Atoi(m[1])
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+
+	if m[3] != "" {
+		if hour < 1 || hour > 12 {
+			return 0, 0, 0
+		}
+		hour %= 12
+		if m[3] == "pm" {
+			hour += 12
+		}
+	}
+
+	if hour > 23 || minute > 59 {
+		return 0, 0, 0
+	}
+
+	return hour, minute, offset + 1
+}
+
+// isHourOnly reports whether a time was written as a bare hour such as "2h",
+// which could also be a duration.
+func isHourOnly(word string) bool {
+	m := timePattern.FindStringSubmatch(word)
+	return m != nil && m[2] == "" && m[3] == "" && strings.HasSuffix(word, "h")
+}
+
+// nextWeekday returns the first given weekday strictly after today.
+func nextWeekday(today time.Time, weekday time.Weekday) time.Time {
+	days := (int(weekday) - int(today.Weekday()) + 7) % 7
+	if days == 0 {
+		days = 7
+	}
+	return today.AddDate(0, 0, days)
+}
+
+func hasPrefix(words, phrase []string) bool {
+	if len(words) < len(phrase) {
+		return false
+	}
+	for i, w := range phrase {
+		if strings.TrimSuffix(words[i], ",") != w {
+			return false
+		}
+	}
+	return true
+}
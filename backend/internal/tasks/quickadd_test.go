@@ -0,0 +1,77 @@
+package tasks
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseQuickAdd(t *testing.T) {
+	loc := time.FixedZone("UTC-3", -3*60*60)
+	// A wednesday afternoon.
+	now := time.Date(2026, 10, 14, 15, 4, 30, 0, loc)
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, loc)
+	}
+
+	tests := []struct {
+		text       string
+		title      string
+		priority   Priority
+		collection string
+		start      time.Time
+	}{
+		// Portuguese.
+		{"Revisar grafos amanhã às 19h !alta #Algoritmos", "Revisar grafos", High, "Algoritmos", at(10, 15, 19, 0)},
+		{"Reunião na sexta às 14h30", "Reunião", Medium, "", at(10, 16, 14, 30)},
+		{"Treino depois de amanhã", "Treino", Medium, "", at(10, 16, defaultQuickAddHour, 0)},
+		{"Entregar relatório em 3 dias !baixa", "Entregar relatório", Low, "", at(10, 17, 9, 0)},
+		{"Planejar semana que vem", "Planejar", Medium, "", at(10, 19, 9, 0)},
+		{"Pagar aluguel 25/12 meio-dia #Casa_Nova", "Pagar aluguel", Medium, "Casa Nova", at(12, 25, 12, 0)},
+		{"Renovar passaporte 10/01", "Renovar passaporte", Medium, "", time.Date(2027, 1, 10, 9, 0, 0, 0, loc)},
+		{"Ligar para o banco hoje 9:15", "Ligar para o banco", Medium, "", at(10, 14, 9, 15)},
+
+		// English.
+		{"Review graphs tomorrow at 7pm !high #Data_Structures", "Review graphs", High, "Data Structures", at(10, 15, 19, 0)},
+		{"Call mom next friday noon", "Call mom", Medium, "", at(10, 16, 12, 0)},
+		{"Dentist in 2 weeks !low", "Dentist", Low, "", at(10, 28, 9, 0)},
+		{"Deploy 2026-11-03 9am", "Deploy", Medium, "", at(11, 3, 9, 0)},
+		{"Standup day after tomorrow 9:30am", "Standup", Medium, "", at(10, 16, 9, 30)},
+
+		// A bare "Nh" is a duration unless "at"/"às" makes it a time.
+		{"Estudar grafos 2h", "Estudar grafos 2h", Medium, "", at(10, 14, 15, 4)},
+		{"Estudar grafos às 2h", "Estudar grafos", Medium, "", at(10, 14, 2, 0)},
+		{"Study graphs at 2h", "Study graphs", Medium, "", at(10, 14, 2, 0)},
+		{"Estudar grafos amanhã 2h", "Estudar grafos 2h", Medium, "", at(10, 15, defaultQuickAddHour, 0)},
+		{"Estudar 2h amanhã", "Estudar 2h", Medium, "", at(10, 15, defaultQuickAddHour, 0)},
+		{"Estudar 2h amanhã às 19h", "Estudar 2h", Medium, "", at(10, 15, 19, 0)},
+		{"Estudar 2h amanhã 19h30", "Estudar 2h", Medium, "", at(10, 15, 19, 30)},
+		{"Estudar grafos 1h30 !alta", "Estudar grafos", High, "", at(10, 14, 1, 30)},
+
+		// Tokens that look like dates, times or priorities but are not.
+		{"Ler capítulo 31/02", "Ler capítulo 31/02", Medium, "", at(10, 14, 15, 4)},
+		{"Treino 13pm", "Treino 13pm", Medium, "", at(10, 14, 15, 4)},
+		{"Treinar !talvez", "Treinar !talvez", Medium, "", at(10, 14, 15, 4)},
+		{"Tocar em 5", "Tocar em 5", Medium, "", at(10, 14, 15, 4)},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseQuickAdd(tt.text, now)
+		if err != nil {
+			t.Errorf("ParseQuickAdd(%q) error = %v", tt.text, err)
+			continue
+		}
+		if got.Title != tt.title || got.Priority != tt.priority || got.Collection != tt.collection || !got.StartTime.Equal(tt.start) {
+			t.Errorf("ParseQuickAdd(%q) = {%q %s %q %v}, want {%q %s %q %v}",
+				tt.text, got.Title, got.Priority, got.Collection, got.StartTime,
+				tt.title, tt.priority, tt.collection, tt.start)
+		}
+	}
+}
+
+func TestParseQuickAddEmptyTitle(t *testing.T) {
+	for _, text := range []string{"", "   ", "!alta #Casa", "amanhã às 19h"} {
+		if _, err := ParseQuickAdd(text, time.Now()); err != ErrEmptyQuickAdd {
+			t.Errorf("ParseQuickAdd(%q) error = %v, want ErrEmptyQuickAdd", text, err)
+		}
+	}
+}
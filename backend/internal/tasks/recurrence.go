@@ -0,0 +1,335 @@
+package tasks
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+// WeekdayRule represents a BYDAY entry. Ordinal is only meaningful for
+// MONTHLY rules (e.g. 2TU = second tuesday, -1FR = last friday); zero means
+// every matching weekday of the period.
+type WeekdayRule struct {
+	Weekday time.Weekday
+	Ordinal int
+}
+
+// RecurrenceRule is the subset of the iCalendar RRULE (RFC 5545) supported by
+// Chronos: FREQ (DAILY, WEEKLY, MONTHLY), INTERVAL, BYDAY, COUNT and UNTIL.
+type RecurrenceRule struct {
+	Freq     Frequency
+	Interval int
+	ByDay    []WeekdayRule
+	Count    int
+	Until    *time.Time
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var untilLayouts = []string{"20060102T150405Z", "20060102T150405", "20060102"}
+
+func ParseRecurrenceRule(raw string) (*RecurrenceRule, error) {
+	raw = strings.TrimSpace(raw)
+	raw = strings.TrimPrefix(strings.ToUpper(raw), "RRULE:")
+	if raw == "" {
+		return nil, ErrInvalidRecurrence
+	}
+
+	rule := &RecurrenceRule{Interval: 1}
+	for _, part := range strings.Split(raw, ";") {
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, ErrInvalidRecurrence
+		}
+
+		switch key {
+		case "FREQ":
+			rule.Freq = Frequency(value)
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, ErrInvalidRecurrence
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, ErrInvalidRecurrence
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return nil, ErrInvalidRecurrence
+			}
+			rule.Until = &until
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				day, err := parseWeekdayRule(code)
+				if err != nil {
+					return nil, err
+				}
+				rule.ByDay = append(rule.ByDay, day)
+			}
+		case "WKST":
+			// Weeks always start on monday.
+		default:
+			return nil, ErrInvalidRecurrence
+		}
+	}
+
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range untilLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			if layout == "20060102" {
+				t = t.Add(24*time.Hour - time.Second)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, ErrInvalidRecurrence
+}
+
+func parseWeekdayRule(code string) (WeekdayRule, error) {
+	code = strings.TrimSpace(code)
+	if len(code) < 2 {
+		return WeekdayRule{}, ErrInvalidRecurrence
+	}
+
+	weekday, ok := weekdayCodes[code[len(code)-2:]]
+	if !ok {
+		return WeekdayRule{}, ErrInvalidRecurrence
+	}
+
+	ordinal := 0
+	if prefix := code[:len(code)-2]; prefix != "" {
+		n, err := strconv.Atoi(prefix)
+		if err != nil || n == 0 || n < -5 || n > 5 {
+			return WeekdayRule{}, ErrInvalidRecurrence
+		}
+		ordinal = n
+	}
+
+	return WeekdayRule{Weekday: weekday, Ordinal: ordinal}, nil
+}
+
+func (r *RecurrenceRule) Validate() error {
+	switch r.Freq {
+	case Daily, Weekly, Monthly:
+	default:
+		return ErrInvalidRecurrence
+	}
+	if r.Interval < 1 {
+		return ErrInvalidRecurrence
+	}
+	if r.Count > 0 && r.Until != nil {
+		return ErrInvalidRecurrence
+	}
+	for _, day := range r.ByDay {
+		if day.Ordinal != 0 && r.Freq != Monthly {
+			return ErrInvalidRecurrence
+		}
+	}
+	return nil
+}
+
+// String renders the rule back in its canonical RRULE form, which is the
+// representation persisted on Task.RecurrenceRule.
+func (r *RecurrenceRule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			code := strings.ToUpper(day.Weekday.String()[:2])
+			if day.Ordinal != 0 {
+				code = strconv.Itoa(day.Ordinal) + code
+			}
+			codes[i] = code
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, fmt.Sprintf("COUNT=%d", r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Between expands the rule anchored at start and returns the start times of
+// the occurrences, each lasting duration, that overlap [from, to); with a
+// zero duration only occurrences starting within the range are returned.
+// Dates are expanded in the location of start, so BYDAY and days of the month
+// follow the user's calendar rather than UTC. The anchor itself is always the
+// first occurrence of the series, as in RFC 5545.
+func (r *RecurrenceRule) Between(start time.Time, duration time.Duration, from, to time.Time) []time.Time {
+	var occurrences []time.Time
+	count := 0
+
+	emit := func(t time.Time) bool {
+		if r.Until != nil && t.After(*r.Until) {
+			return false
+		}
+		count++
+		if r.Count > 0 && count > r.Count {
+			return false
+		}
+		if t.Before(to) && (!t.Before(from) || t.Add(duration).After(from)) {
+			occurrences = append(occurrences, t)
+		}
+		return true
+	}
+
+	if !emit(start) {
+		return occurrences
+	}
+
+	for period := 0; ; period++ {
+		periodStart := r.periodStart(start, period)
+		if !periodStart.Before(to) {
+			break
+		}
+		if r.Until != nil && periodStart.After(*r.Until) {
+			break
+		}
+
+		for _, candidate := range r.candidates(start, periodStart) {
+			if !candidate.After(start) {
+				continue
+			}
+			if !candidate.Before(to) {
+				return occurrences
+			}
+			if !emit(candidate) {
+				return occurrences
+			}
+		}
+	}
+
+	return occurrences
+}
+
+// Includes reports whether t is one of the occurrences generated from start.
+func (r *RecurrenceRule) Includes(start, t time.Time) bool {
+	matches := r.Between(start, 0, t, t.Add(time.Second))
+	return len(matches) > 0 && matches[0].Equal(t)
+}
+
+func (r *RecurrenceRule) periodStart(start time.Time, period int) time.Time {
+	y, m, d := start.Date()
+	loc := start.Location()
+
+	switch r.Freq {
+	case Weekly:
+		offset := (int(start.Weekday()) + 6) % 7
+		return time.Date(y, m, d-offset+7*r.Interval*period, 0, 0, 0, 0, loc)
+	case Monthly:
+		return time.Date(y, m+time.Month(r.Interval*period), 1, 0, 0, 0, 0, loc)
+	default:
+		return time.Date(y, m, d+r.Interval*period, 0, 0, 0, 0, loc)
+	}
+}
+
+func (r *RecurrenceRule) candidates(start, periodStart time.Time) []time.Time {
+	hour, minute, sec := start.Clock()
+	at := func(day time.Time) time.Time {
+		y, m, d := day.Date()
+		return time.Date(y, m, d, hour, minute, sec, start.Nanosecond(), start.Location())
+	}
+
+	var days []time.Time
+	switch r.Freq {
+	case Daily:
+		if len(r.ByDay) == 0 || r.matchesWeekday(periodStart.Weekday()) {
+			days = append(days, periodStart)
+		}
+	case Weekly:
+		if len(r.ByDay) == 0 {
+			days = append(days, periodStart.AddDate(0, 0, (int(start.Weekday())+6)%7))
+			break
+		}
+		for _, day := range r.ByDay {
+			days = append(days, periodStart.AddDate(0, 0, (int(day.Weekday)+6)%7))
+		}
+	case Monthly:
+		days = r.monthlyDays(start, periodStart)
+	}
+
+	result := make([]time.Time, 0, len(days))
+	for _, day := range days {
+		result = append(result, at(day))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Before(result[j]) })
+	return result
+}
+
+func (r *RecurrenceRule) monthlyDays(start, monthStart time.Time) []time.Time {
+	if len(r.ByDay) == 0 {
+		day := monthStart.AddDate(0, 0, start.Day()-1)
+		if day.Month() != monthStart.Month() {
+			return nil
+		}
+		return []time.Time{day}
+	}
+
+	monthEnd := monthStart.AddDate(0, 1, -1)
+	var days []time.Time
+	for _, rule := range r.ByDay {
+		var matches []time.Time
+		for day := monthStart; !day.After(monthEnd); day = day.AddDate(0, 0, 1) {
+			if day.Weekday() == rule.Weekday {
+				matches = append(matches, day)
+			}
+		}
+
+		switch {
+		case rule.Ordinal == 0:
+			days = append(days, matches...)
+		case rule.Ordinal > 0 && rule.Ordinal <= len(matches):
+			days = append(days, matches[rule.Ordinal-1])
+		case rule.Ordinal < 0 && -rule.Ordinal <= len(matches):
+			days = append(days, matches[len(matches)+rule.Ordinal])
+		}
+	}
+	return days
+}
+
+func (r *RecurrenceRule) matchesWeekday(weekday time.Weekday) bool {
+	for _, day := range r.ByDay {
+		if day.Weekday == weekday {
+			return true
+		}
+	}
+	return false
+}
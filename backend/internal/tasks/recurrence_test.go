@@ -0,0 +1,240 @@
+package tasks
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseRecurrenceRule(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{"FREQ=DAILY", "FREQ=DAILY", false},
+		{"rrule:freq=daily;interval=2", "FREQ=DAILY;INTERVAL=2", false},
+		{"FREQ=WEEKLY;BYDAY=MO,WE;WKST=SU", "FREQ=WEEKLY;BYDAY=MO,WE", false},
+		{"FREQ=MONTHLY;BYDAY=2TU,-1FR;COUNT=6", "FREQ=MONTHLY;BYDAY=2TU,-1FR;COUNT=6", false},
+		{"FREQ=DAILY;UNTIL=20260131", "FREQ=DAILY;UNTIL=20260131T235959Z", false},
+		{"FREQ=WEEKLY;UNTIL=20260131T120000Z", "FREQ=WEEKLY;UNTIL=20260131T120000Z", false},
+		{"", "", true},
+		{"FREQ=YEARLY", "", true},
+		{"INTERVAL=2", "", true},
+		{"FREQ=DAILY;INTERVAL=0", "", true},
+		{"FREQ=DAILY;COUNT=2;UNTIL=20260131", "", true},
+		{"FREQ=WEEKLY;BYDAY=2MO", "", true},
+		{"FREQ=MONTHLY;BYDAY=6MO", "", true},
+		{"FREQ=WEEKLY;BYDAY=XX", "", true},
+		{"FREQ=DAILY;BYHOUR=9", "", true},
+		{"FREQ=DAILY;COUNT=", "", true},
+	}
+
+	for _, tt := range tests {
+		rule, err := ParseRecurrenceRule(tt.raw)
+		if tt.wantErr {
+			if err != ErrInvalidRecurrence {
+				t.Errorf("ParseRecurrenceRule(%q) error = %v, want ErrInvalidRecurrence", tt.raw, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseRecurrenceRule(%q) error = %v", tt.raw, err)
+			continue
+		}
+		if got := rule.String(); got != tt.want {
+			t.Errorf("ParseRecurrenceRule(%q).String() = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func date(year int, month time.Month, day, hour, minute int, loc *time.Location) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, loc)
+}
+
+func TestRecurrenceRuleBetween(t *testing.T) {
+	// 2026-01-05 is a monday.
+	utc := time.UTC
+	saoPaulo := time.FixedZone("UTC-3", -3*60*60)
+
+	tests := []struct {
+		name     string
+		rule     string
+		start    time.Time
+		duration time.Duration
+		from, to time.Time
+		want     []time.Time
+	}{
+		{
+			name:  "daily with count",
+			rule:  "FREQ=DAILY;COUNT=3",
+			start: date(2026, 1, 5, 9, 0, utc),
+			from:  date(2026, 1, 1, 0, 0, utc),
+			to:    date(2026, 2, 1, 0, 0, utc),
+			want: []time.Time{
+				date(2026, 1, 5, 9, 0, utc),
+				date(2026, 1, 6, 9, 0, utc),
+				date(2026, 1, 7, 9, 0, utc),
+			},
+		},
+		{
+			name:  "count includes occurrences before the range",
+			rule:  "FREQ=DAILY;COUNT=3",
+			start: date(2026, 1, 5, 9, 0, utc),
+			from:  date(2026, 1, 6, 0, 0, utc),
+			to:    date(2026, 2, 1, 0, 0, utc),
+			want: []time.Time{
+				date(2026, 1, 6, 9, 0, utc),
+				date(2026, 1, 7, 9, 0, utc),
+			},
+		},
+		{
+			name:  "date-only until is inclusive",
+			rule:  "FREQ=DAILY;INTERVAL=2;UNTIL=20260109",
+			start: date(2026, 1, 5, 20, 0, utc),
+			from:  date(2026, 1, 1, 0, 0, utc),
+			to:    date(2026, 2, 1, 0, 0, utc),
+			want: []time.Time{
+				date(2026, 1, 5, 20, 0, utc),
+				date(2026, 1, 7, 20, 0, utc),
+				date(2026, 1, 9, 20, 0, utc),
+			},
+		},
+		{
+			name:  "every other week on monday and wednesday",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE",
+			start: date(2026, 1, 5, 7, 30, utc),
+			from:  date(2026, 1, 1, 0, 0, utc),
+			to:    date(2026, 1, 26, 0, 0, utc),
+			want: []time.Time{
+				date(2026, 1, 5, 7, 30, utc),
+				date(2026, 1, 7, 7, 30, utc),
+				date(2026, 1, 19, 7, 30, utc),
+				date(2026, 1, 21, 7, 30, utc),
+			},
+		},
+		{
+			name:  "monthly on the 31st skips shorter months",
+			rule:  "FREQ=MONTHLY",
+			start: date(2026, 1, 31, 18, 0, utc),
+			from:  date(2026, 1, 1, 0, 0, utc),
+			to:    date(2026, 6, 1, 0, 0, utc),
+			want: []time.Time{
+				date(2026, 1, 31, 18, 0, utc),
+				date(2026, 3, 31, 18, 0, utc),
+				date(2026, 5, 31, 18, 0, utc),
+			},
+		},
+		{
+			name:  "monthly on the last friday",
+			rule:  "FREQ=MONTHLY;BYDAY=-1FR;COUNT=3",
+			start: date(2026, 1, 30, 10, 0, utc),
+			from:  date(2026, 1, 1, 0, 0, utc),
+			to:    date(2027, 1, 1, 0, 0, utc),
+			want: []time.Time{
+				date(2026, 1, 30, 10, 0, utc),
+				date(2026, 2, 27, 10, 0, utc),
+				date(2026, 3, 27, 10, 0, utc),
+			},
+		},
+		{
+			name:  "weekdays follow the time zone of start",
+			rule:  "FREQ=WEEKLY;BYDAY=MO",
+			start: date(2026, 1, 5, 22, 0, saoPaulo),
+			from:  date(2026, 1, 1, 0, 0, utc),
+			to:    date(2026, 1, 21, 0, 0, utc),
+			want: []time.Time{
+				date(2026, 1, 5, 22, 0, saoPaulo),
+				date(2026, 1, 12, 22, 0, saoPaulo),
+				date(2026, 1, 19, 22, 0, saoPaulo),
+			},
+		},
+		{
+			name:     "occurrence running at the start of the range",
+			rule:     "FREQ=DAILY",
+			start:    date(2026, 1, 5, 23, 0, utc),
+			duration: 2 * time.Hour,
+			from:     date(2026, 1, 7, 0, 0, utc),
+			to:       date(2026, 1, 8, 0, 0, utc),
+			want: []time.Time{
+				date(2026, 1, 6, 23, 0, utc),
+				date(2026, 1, 7, 23, 0, utc),
+			},
+		},
+		{
+			name:  "range before the series",
+			rule:  "FREQ=DAILY",
+			start: date(2026, 1, 5, 9, 0, utc),
+			from:  date(2025, 12, 1, 0, 0, utc),
+			to:    date(2026, 1, 5, 9, 0, utc),
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRecurrenceRule(tt.rule)
+			if err != nil {
+				t.Fatalf("ParseRecurrenceRule(%q): %v", tt.rule, err)
+			}
+
+			got := rule.Between(tt.start, tt.duration, tt.from, tt.to)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Between = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("Between[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRecurrenceRuleBetweenKeepsWeekdayInLocation(t *testing.T) {
+	saoPaulo := time.FixedZone("UTC-3", -3*60*60)
+	rule, err := ParseRecurrenceRule("FREQ=WEEKLY;BYDAY=MO")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Stored as UTC the anchor falls on a tuesday; expanded in the user's
+	// zone every occurrence must still be a monday evening there.
+	start := date(2026, 1, 5, 22, 0, saoPaulo).UTC()
+	var weekdays []time.Weekday
+	for _, occurrence := range rule.Between(start.In(saoPaulo), 0, start, start.AddDate(0, 0, 21)) {
+		weekdays = append(weekdays, occurrence.In(saoPaulo).Weekday())
+	}
+
+	want := []time.Weekday{time.Monday, time.Monday, time.Monday}
+	if !reflect.DeepEqual(weekdays, want) {
+		t.Errorf("weekdays = %v, want %v", weekdays, want)
+	}
+}
+
+func TestRecurrenceRuleIncludes(t *testing.T) {
+	rule, err := ParseRecurrenceRule("FREQ=WEEKLY;BYDAY=MO,WE;COUNT=4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := date(2026, 1, 5, 9, 0, time.UTC)
+
+	tests := []struct {
+		t    time.Time
+		want bool
+	}{
+		{date(2026, 1, 5, 9, 0, time.UTC), true},
+		{date(2026, 1, 7, 9, 0, time.UTC), true},
+		{date(2026, 1, 14, 9, 0, time.UTC), true},
+		{date(2026, 1, 6, 9, 0, time.UTC), false},
+		{date(2026, 1, 7, 10, 0, time.UTC), false},
+		{date(2026, 1, 19, 9, 0, time.UTC), false},
+		{date(2025, 12, 29, 9, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		if got := rule.Includes(start, tt.t); got != tt.want {
+			t.Errorf("Includes(%v) = %v, want %v", tt.t, got, tt.want)
+		}
+	}
+}
@@ -2,18 +2,72 @@ package tasks
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository interface {
+	Transaction(ctx context.Context, fn func(repo Repository) error) error
 	Create(ctx context.Context, task *Task) error
 	GetByID(ctx context.Context, id, userID uuid.UUID) (*Task, error)
 	GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]Task, error)
+	List(ctx context.Context, userID uuid.UUID, filter TaskFilter) ([]Task, error)
 	GetByCollectionID(ctx context.Context, userID, collectionID uuid.UUID) ([]Task, error)
+	GetInRange(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]Task, error)
+	FindExternalUIDs(ctx context.Context, userID uuid.UUID, uids []string) ([]string, error)
 	Update(ctx context.Context, task *Task) error
 	Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+	GetTrash(ctx context.Context, userID uuid.UUID) ([]Task, error)
+	GetDeletedByID(ctx context.Context, id, userID uuid.UUID) (*Task, error)
+	Restore(ctx context.Context, task *Task) error
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+
+	GetSubtasks(ctx context.Context, parentID, userID uuid.UUID) ([]Task, error)
+	CountSubtasks(ctx context.Context, userID uuid.UUID, parentIDs []uuid.UUID) (map[uuid.UUID]SubtaskCount, error)
+	NextSubtaskPosition(ctx context.Context, parentID, userID uuid.UUID) (int, error)
+	UpdatePositions(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error
+
+	NextBoardPosition(ctx context.Context, userID uuid.UUID, collectionID *uuid.UUID, status Status) (float64, error)
+	RebalanceBoard(ctx context.Context, userID uuid.UUID, collectionID *uuid.UUID, status Status) error
+
+	GetOccurrences(ctx context.Context, userID uuid.UUID, taskIDs []uuid.UUID, from, to time.Time) ([]TaskOccurrence, error)
+	GetOccurrence(ctx context.Context, userID, taskID uuid.UUID, occurrenceDate time.Time) (*TaskOccurrence, error)
+	SaveOccurrence(ctx context.Context, occurrence *TaskOccurrence) error
+	ShiftOccurrences(ctx context.Context, userID, taskID uuid.UUID, days int) error
+
+	CreateTimeEntry(ctx context.Context, entry *TimeEntry) error
+	UpdateTimeEntry(ctx context.Context, entry *TimeEntry) error
+	DeleteTimeEntry(ctx context.Context, id, userID uuid.UUID) error
+	GetTimeEntry(ctx context.Context, id, userID uuid.UUID) (*TimeEntry, error)
+	GetTimeEntries(ctx context.Context, taskID, userID uuid.UUID) ([]TimeEntry, error)
+	GetRunningTimeEntry(ctx context.Context, userID uuid.UUID) (*TimeEntry, error)
+	SumTrackedSeconds(ctx context.Context, userID uuid.UUID, taskIDs []uuid.UUID) (map[uuid.UUID]int64, error)
+
+	CreateComment(ctx context.Context, comment *TaskComment) error
+	UpdateComment(ctx context.Context, comment *TaskComment) error
+	DeleteComment(ctx context.Context, id, userID uuid.UUID) error
+	GetComment(ctx context.Context, id, userID uuid.UUID) (*TaskComment, error)
+	GetComments(ctx context.Context, taskID, userID uuid.UUID) ([]TaskComment, error)
+
+	LockDependencies(ctx context.Context, userID uuid.UUID) error
+	AddDependency(ctx context.Context, dependency *TaskDependency) error
+	RemoveDependency(ctx context.Context, taskID, blockedByID, userID uuid.UUID) (bool, error)
+	GetBlockers(ctx context.Context, taskID, userID uuid.UUID) ([]Task, error)
+	GetDependencyEdges(ctx context.Context, userID uuid.UUID, taskIDs []uuid.UUID) ([]DependencyEdge, error)
+
+	GetOverdue(ctx context.Context, userID uuid.UUID, before time.Time) ([]Task, error)
+	CountOpenTasksPerDay(ctx context.Context, userID uuid.UUID, from, to time.Time, loc *time.Location) (map[string]int, error)
+	GetRolloverSettings(ctx context.Context, userID uuid.UUID) (*RolloverSettings, error)
+	GetActiveRolloverSettings(ctx context.Context) ([]RolloverSettings, error)
+	SaveRolloverSettings(ctx context.Context, settings *RolloverSettings) error
+	CreateRolloverLog(ctx context.Context, log *RolloverLog) error
+	GetRolloverLogs(ctx context.Context, userID uuid.UUID, limit int) ([]RolloverLog, error)
 }
 
 type repository struct {
@@ -24,6 +78,13 @@ func NewRepository(db *gorm.DB) Repository {
 	return &repository{db: db}
 }
 
+// Transaction runs fn with a repository bound to a database transaction.
+func (r *repository) Transaction(ctx context.Context, fn func(repo Repository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&repository{db: tx})
+	})
+}
+
 func (r *repository) Create(ctx context.Context, task *Task) error {
 	return r.db.WithContext(ctx).Create(task).Error
 }
@@ -56,11 +117,65 @@ func (r *repository) GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]Ta
 	return tasks, nil
 }
 
+// List applies the filter and returns up to filter.Limit+1 tasks so the
+// caller can tell whether another page exists, or every task when Limit is
+// zero.
+func (r *repository) List(ctx context.Context, userID uuid.UUID, filter TaskFilter) ([]Task, error) {
+	var tasks []Task
+
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+	if filter.Priority != nil {
+		query = query.Where("priority = ?", *filter.Priority)
+	}
+	if filter.CollectionID != nil {
+		query = query.Where("collection_id = ?", *filter.CollectionID)
+	}
+	if filter.From != nil {
+		query = query.Where("start_time >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("start_time < ?", *filter.To)
+	}
+	if filter.Search != "" {
+		query = query.Where("name ILIKE ?", "%"+escapeLike(filter.Search)+"%")
+	}
+
+	column := filter.Sort.column()
+	direction, comparison := "ASC", ">"
+	if filter.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	if filter.Cursor != nil {
+		value, err := filter.Cursor.sortValue()
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where(fmt.Sprintf("(%s, id) %s (?, ?)", column, comparison), value, filter.Cursor.ID)
+	}
+
+	query = query.Order(fmt.Sprintf("%s %s, id %s", column, direction, direction))
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit + 1)
+	}
+
+	if err := query.Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
 func (r *repository) GetByCollectionID(ctx context.Context, userID, collectionID uuid.UUID) ([]Task, error) {
 	var tasks []Task
 
 	err := r.db.WithContext(ctx).
 		Where("user_id = ? AND collection_id = ?", userID, collectionID).
+		Order("board_position ASC, created_at ASC").
 		Find(&tasks).Error
 
 	if err != nil {
@@ -70,12 +185,627 @@ func (r *repository) GetByCollectionID(ctx context.Context, userID, collectionID
 	return tasks, nil
 }
 
+// GetInRange returns the non-recurring tasks overlapping [from, to) plus every
+// recurring task whose series starts before to, since those have to be
+// expanded to know whether they produce occurrences in the range.
+func (r *repository) GetInRange(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]Task, error) {
+	var tasks []Task
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND start_time < ?", userID, to).
+		Where("recurrence_rule IS NOT NULL OR COALESCE(end_time, start_time) >= ?", from).
+		Order("start_time ASC").
+		Find(&tasks).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// FindExternalUIDs also looks at tasks in the trash, which still hold their
+// UID in the unique index.
+func (r *repository) FindExternalUIDs(ctx context.Context, userID uuid.UUID, uids []string) ([]string, error) {
+	var found []string
+	if len(uids) == 0 {
+		return found, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&Task{}).
+		Where("user_id = ? AND external_uid IN ?", userID, uids).
+		Pluck("external_uid", &found).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
+
 func (r *repository) Update(ctx context.Context, task *Task) error {
 	return r.db.WithContext(ctx).Save(task).Error
 }
 
+// Delete moves the task together with its live subtask tree to the trash.
+// The whole tree shares the same DeletedAt, which is how Restore finds it
+// again; occurrences, time entries, comments and dependencies are kept until
+// the tree is purged.
 func (r *repository) Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE tree AS (
+			SELECT id FROM tasks WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT t.id FROM tasks t JOIN tree ON t.parent_id = tree.id WHERE t.deleted_at IS NULL
+		)
+		SELECT id FROM tree`, id, userID).
+		Scan(&ids).Error
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
 	return r.db.WithContext(ctx).
-		Where("id = ? AND user_id = ?", id, userID).
+		Where("id IN ? AND user_id = ?", ids, userID).
 		Delete(&Task{}).Error
 }
+
+// GetTrash lists the deleted tasks whose parent is not deleted as well, so a
+// tree deleted at once shows up as its root only.
+func (r *repository) GetTrash(ctx context.Context, userID uuid.UUID) ([]Task, error) {
+	var tasks []Task
+
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+		Where("NOT EXISTS (SELECT 1 FROM tasks p WHERE p.id = tasks.parent_id AND p.deleted_at IS NOT NULL)").
+		Order("deleted_at DESC").
+		Find(&tasks).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+func (r *repository) GetDeletedByID(ctx context.Context, id, userID uuid.UUID) (*Task, error) {
+	var task Task
+
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", id, userID).
+		First(&task).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+// Restore brings back the task and the subtasks deleted together with it.
+// Subtasks that were already in the trash before stay there.
+func (r *repository) Restore(ctx context.Context, task *Task) error {
+	return r.db.WithContext(ctx).Exec(`
+		WITH RECURSIVE tree AS (
+			SELECT id FROM tasks WHERE id = ? AND user_id = ?
+			UNION ALL
+			SELECT t.id FROM tasks t JOIN tree ON t.parent_id = tree.id WHERE t.deleted_at = ?
+		)
+		UPDATE tasks SET deleted_at = NULL, updated_at = ? WHERE id IN (SELECT id FROM tree)`,
+		task.ID, task.UserID, task.DeletedAt.Time, time.Now()).
+		Error
+}
+
+// PurgeDeleted permanently removes the tasks deleted before the given
+// instant, for every user, together with the rows that belong to them.
+func (r *repository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []uuid.UUID
+		err := tx.Unscoped().
+			Model(&Task{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		if err := tx.Where("task_id IN ?", ids).Delete(&TaskOccurrence{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("task_id IN ?", ids).Delete(&TimeEntry{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("task_id IN ?", ids).Delete(&TaskComment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("task_id IN ? OR blocked_by_id IN ?", ids, ids).Delete(&TaskDependency{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Unscoped().Where("id IN ?", ids).Delete(&Task{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+
+	return purged, err
+}
+
+func (r *repository) GetSubtasks(ctx context.Context, parentID, userID uuid.UUID) ([]Task, error) {
+	var tasks []Task
+
+	err := r.db.WithContext(ctx).
+		Where("parent_id = ? AND user_id = ?", parentID, userID).
+		Order("position ASC, created_at ASC").
+		Find(&tasks).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+func (r *repository) CountSubtasks(ctx context.Context, userID uuid.UUID, parentIDs []uuid.UUID) (map[uuid.UUID]SubtaskCount, error) {
+	counts := make(map[uuid.UUID]SubtaskCount)
+	if len(parentIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []SubtaskCount
+	err := r.db.WithContext(ctx).
+		Model(&Task{}).
+		Select("parent_id, COUNT(*) AS total, COUNT(finished_at) AS done").
+		Where("user_id = ? AND parent_id IN ?", userID, parentIDs).
+		Group("parent_id").
+		Scan(&rows).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.ParentID] = row
+	}
+
+	return counts, nil
+}
+
+func (r *repository) NextSubtaskPosition(ctx context.Context, parentID, userID uuid.UUID) (int, error) {
+	var position int
+
+	err := r.db.WithContext(ctx).
+		Model(&Task{}).
+		Select("COALESCE(MAX(position), -1) + 1").
+		Where("parent_id = ? AND user_id = ?", parentID, userID).
+		Scan(&position).Error
+
+	return position, err
+}
+
+// UpdatePositions stores the index of each id as its position.
+func (r *repository) UpdatePositions(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for position, id := range ids {
+			err := tx.Model(&Task{}).
+				Where("id = ? AND user_id = ?", id, userID).
+				Update("position", position).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// boardColumn restricts a query to the tasks of one column of a board.
+func boardColumn(userID uuid.UUID, collectionID *uuid.UUID, status Status) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("user_id = ? AND status = ?", userID, status)
+		if collectionID == nil {
+			return db.Where("collection_id IS NULL")
+		}
+		return db.Where("collection_id = ?", *collectionID)
+	}
+}
+
+func (r *repository) NextBoardPosition(ctx context.Context, userID uuid.UUID, collectionID *uuid.UUID, status Status) (float64, error) {
+	var position float64
+
+	err := r.db.WithContext(ctx).
+		Model(&Task{}).
+		Select("COALESCE(MAX(board_position), 0) + ?", boardPositionStep).
+		Scopes(boardColumn(userID, collectionID, status)).
+		Scan(&position).Error
+
+	return position, err
+}
+
+// RebalanceBoard renumbers a board column with evenly spaced positions,
+// keeping its current order.
+func (r *repository) RebalanceBoard(ctx context.Context, userID uuid.UUID, collectionID *uuid.UUID, status Status) error {
+	ranked := r.db.Model(&Task{}).
+		Select("id, ROW_NUMBER() OVER (ORDER BY board_position, created_at, id) AS seq").
+		Scopes(boardColumn(userID, collectionID, status))
+
+	return r.db.WithContext(ctx).
+		Exec("UPDATE tasks SET board_position = ranked.seq * ? FROM (?) AS ranked WHERE tasks.id = ranked.id", boardPositionStep, ranked).
+		Error
+}
+
+func (r *repository) GetOccurrences(ctx context.Context, userID uuid.UUID, taskIDs []uuid.UUID, from, to time.Time) ([]TaskOccurrence, error) {
+	var occurrences []TaskOccurrence
+	if len(taskIDs) == 0 {
+		return occurrences, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND task_id IN ? AND occurrence_date >= ? AND occurrence_date < ?", userID, taskIDs, from, to).
+		Find(&occurrences).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return occurrences, nil
+}
+
+// GetOccurrence returns the override of one occurrence, or nil when the
+// occurrence still follows the series.
+func (r *repository) GetOccurrence(ctx context.Context, userID, taskID uuid.UUID, occurrenceDate time.Time) (*TaskOccurrence, error) {
+	var occurrences []TaskOccurrence
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND task_id = ? AND occurrence_date = ?", userID, taskID, occurrenceDate).
+		Limit(1).
+		Find(&occurrences).Error
+
+	if err != nil || len(occurrences) == 0 {
+		return nil, err
+	}
+
+	return &occurrences[0], nil
+}
+
+func (r *repository) SaveOccurrence(ctx context.Context, occurrence *TaskOccurrence) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "task_id"}, {Name: "occurrence_date"}},
+			DoUpdates: clause.AssignmentColumns([]string{"status", "finished_at", "updated_at"}),
+		}).
+		Create(occurrence).Error
+}
+
+// ShiftOccurrences moves every override of a recurring task by the given
+// number of days. The rows are rewritten instead of updated in place, since
+// an in-place update can collide with the next occurrence on
+// idx_task_occurrence before that one has moved.
+func (r *repository) ShiftOccurrences(ctx context.Context, userID, taskID uuid.UUID, days int) error {
+	var occurrences []TaskOccurrence
+	db := r.db.WithContext(ctx)
+
+	if err := db.Where("user_id = ? AND task_id = ?", userID, taskID).Find(&occurrences).Error; err != nil {
+		return err
+	}
+	if len(occurrences) == 0 {
+		return nil
+	}
+
+	if err := db.Where("user_id = ? AND task_id = ?", userID, taskID).Delete(&TaskOccurrence{}).Error; err != nil {
+		return err
+	}
+
+	for i := range occurrences {
+		occurrences[i].OccurrenceDate = occurrences[i].OccurrenceDate.AddDate(0, 0, days)
+	}
+
+	return db.Create(&occurrences).Error
+}
+
+func (r *repository) CreateTimeEntry(ctx context.Context, entry *TimeEntry) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+func (r *repository) UpdateTimeEntry(ctx context.Context, entry *TimeEntry) error {
+	return r.db.WithContext(ctx).Save(entry).Error
+}
+
+func (r *repository) DeleteTimeEntry(ctx context.Context, id, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		Delete(&TimeEntry{}).Error
+}
+
+func (r *repository) GetTimeEntry(ctx context.Context, id, userID uuid.UUID) (*TimeEntry, error) {
+	var entry TimeEntry
+
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&entry).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+func (r *repository) GetTimeEntries(ctx context.Context, taskID, userID uuid.UUID) ([]TimeEntry, error) {
+	var entries []TimeEntry
+
+	err := r.db.WithContext(ctx).
+		Where("task_id = ? AND user_id = ?", taskID, userID).
+		Order("started_at DESC").
+		Find(&entries).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *repository) CreateComment(ctx context.Context, comment *TaskComment) error {
+	return r.db.WithContext(ctx).Create(comment).Error
+}
+
+func (r *repository) UpdateComment(ctx context.Context, comment *TaskComment) error {
+	return r.db.WithContext(ctx).Save(comment).Error
+}
+
+func (r *repository) DeleteComment(ctx context.Context, id, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		Delete(&TaskComment{}).Error
+}
+
+func (r *repository) GetComment(ctx context.Context, id, userID uuid.UUID) (*TaskComment, error) {
+	var comment TaskComment
+
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&comment).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+func (r *repository) GetComments(ctx context.Context, taskID, userID uuid.UUID) ([]TaskComment, error) {
+	var comments []TaskComment
+
+	err := r.db.WithContext(ctx).
+		Where("task_id = ? AND user_id = ?", taskID, userID).
+		Order("created_at ASC").
+		Find(&comments).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+func (r *repository) GetRunningTimeEntry(ctx context.Context, userID uuid.UUID) (*TimeEntry, error) {
+	var entry TimeEntry
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND ended_at IS NULL", userID).
+		First(&entry).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// SumTrackedSeconds totals the time entries of each task, counting running
+// timers up to now.
+func (r *repository) SumTrackedSeconds(ctx context.Context, userID uuid.UUID, taskIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	totals := make(map[uuid.UUID]int64)
+	if len(taskIDs) == 0 {
+		return totals, nil
+	}
+
+	var rows []struct {
+		TaskID  uuid.UUID
+		Seconds int64
+	}
+	err := r.db.WithContext(ctx).
+		Model(&TimeEntry{}).
+		Select("task_id, CAST(SUM(EXTRACT(EPOCH FROM COALESCE(ended_at, NOW()) - started_at)) AS BIGINT) AS seconds").
+		Where("user_id = ? AND task_id IN ?", userID, taskIDs).
+		Group("task_id").
+		Scan(&rows).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		totals[row.TaskID] = row.Seconds
+	}
+
+	return totals, nil
+}
+
+// LockDependencies takes a transaction-scoped advisory lock on the user's
+// dependency graph, so concurrent additions cannot each pass the cycle check
+// and close a cycle together. It must run inside Transaction.
+func (r *repository) LockDependencies(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "task_dependencies:"+userID.String()).
+		Error
+}
+
+func (r *repository) AddDependency(ctx context.Context, dependency *TaskDependency) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(dependency).Error
+}
+
+func (r *repository) RemoveDependency(ctx context.Context, taskID, blockedByID, userID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("task_id = ? AND blocked_by_id = ? AND user_id = ?", taskID, blockedByID, userID).
+		Delete(&TaskDependency{})
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *repository) GetBlockers(ctx context.Context, taskID, userID uuid.UUID) ([]Task, error) {
+	var tasks []Task
+
+	err := r.db.WithContext(ctx).
+		Joins("JOIN task_dependencies d ON d.blocked_by_id = tasks.id").
+		Where("d.task_id = ? AND d.user_id = ?", taskID, userID).
+		Order("tasks.start_time ASC").
+		Find(&tasks).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// GetDependencyEdges returns the dependencies of the given tasks, or of every
+// task of the user when taskIDs is nil. Edges of tasks in the trash are kept
+// so cycle detection still sees them once restored, but a deleted blocker
+// never counts as pending.
+func (r *repository) GetDependencyEdges(ctx context.Context, userID uuid.UUID, taskIDs []uuid.UUID) ([]DependencyEdge, error) {
+	var edges []DependencyEdge
+	if taskIDs != nil && len(taskIDs) == 0 {
+		return edges, nil
+	}
+
+	query := r.db.WithContext(ctx).
+		Table("task_dependencies d").
+		Select("d.task_id, d.blocked_by_id, t.finished_at IS NULL AND t.deleted_at IS NULL AS pending").
+		Joins("JOIN tasks t ON t.id = d.blocked_by_id").
+		Where("d.user_id = ?", userID)
+
+	if taskIDs != nil {
+		query = query.Where("d.task_id IN ?", taskIDs)
+	}
+
+	if err := query.Scan(&edges).Error; err != nil {
+		return nil, err
+	}
+
+	return edges, nil
+}
+
+// GetOverdue returns the unfinished, non-recurring tasks whose end time is
+// before the given instant, oldest first.
+func (r *repository) GetOverdue(ctx context.Context, userID uuid.UUID, before time.Time) ([]Task, error) {
+	var tasks []Task
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND finished_at IS NULL AND recurrence_rule IS NULL", userID).
+		Where("end_time IS NOT NULL AND end_time < ?", before).
+		Order("end_time ASC").
+		Find(&tasks).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// CountOpenTasksPerDay counts the unfinished tasks starting on each day of
+// [from, to), keyed by the date (YYYY-MM-DD) in loc.
+func (r *repository) CountOpenTasksPerDay(ctx context.Context, userID uuid.UUID, from, to time.Time, loc *time.Location) (map[string]int, error) {
+	var rows []struct {
+		Day   string
+		Total int
+	}
+
+	err := r.db.WithContext(ctx).
+		Model(&Task{}).
+		Select("to_char(start_time AT TIME ZONE ?, 'YYYY-MM-DD') AS day, COUNT(*) AS total", loc.String()).
+		Where("user_id = ? AND finished_at IS NULL AND start_time >= ? AND start_time < ?", userID, from, to).
+		Group("day").
+		Scan(&rows).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Day] = row.Total
+	}
+
+	return counts, nil
+}
+
+func (r *repository) GetRolloverSettings(ctx context.Context, userID uuid.UUID) (*RolloverSettings, error) {
+	var settings RolloverSettings
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		First(&settings).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+func (r *repository) GetActiveRolloverSettings(ctx context.Context) ([]RolloverSettings, error) {
+	var settings []RolloverSettings
+
+	err := r.db.WithContext(ctx).
+		Where("policy <> ?", RolloverLeave).
+		Find(&settings).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+func (r *repository) SaveRolloverSettings(ctx context.Context, settings *RolloverSettings) error {
+	return r.db.WithContext(ctx).Save(settings).Error
+}
+
+func (r *repository) CreateRolloverLog(ctx context.Context, log *RolloverLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
+
+func (r *repository) GetRolloverLogs(ctx context.Context, userID uuid.UUID, limit int) ([]RolloverLog, error) {
+	var logs []RolloverLog
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&logs).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+// isUniqueViolation reports whether err is Postgres rejecting a row that
+// breaks a unique index.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -0,0 +1,45 @@
+package tasks
+
+import "time"
+
+// maxFreeDaySearch bounds how many days ahead NEXT_FREE_DAY looks for a
+// day without open tasks before falling back to today.
+const maxFreeDaySearch = 60
+
+func startOfDay(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// daysBetween counts calendar days from one date to another, ignoring DST
+// changes in between.
+func daysBetween(from, to time.Time) int {
+	a := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	b := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(b.Sub(a).Hours() / 24)
+}
+
+// nextFreeDay returns the first day from today on without open tasks,
+// according to counts keyed by YYYY-MM-DD, and reserves it so the next
+// overdue task goes to a different day.
+func nextFreeDay(today time.Time, counts map[string]int) time.Time {
+	for i := 0; i < maxFreeDaySearch; i++ {
+		day := today.AddDate(0, 0, i)
+		key := day.Format("2006-01-02")
+		if counts[key] == 0 {
+			counts[key]++
+			return day
+		}
+	}
+	return today
+}
+
+// shiftTask moves the task by whole calendar days in loc, keeping its local
+// start and end times.
+func shiftTask(task *Task, days int, loc *time.Location) {
+	task.StartTime = task.StartTime.In(loc).AddDate(0, 0, days)
+	if task.EndTime != nil {
+		end := task.EndTime.In(loc).AddDate(0, 0, days)
+		task.EndTime = &end
+	}
+}
@@ -2,28 +2,90 @@ package tasks
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos/internal/auth"
+	"github.com/saulo-duarte/chronos/internal/collections"
 	"github.com/saulo-duarte/chronos/internal/shared/middlewares"
+	"gorm.io/gorm"
 )
 
 type Service interface {
 	Create(ctx context.Context, dto *CreateTaskDTO) (*TaskResponseDTO, error)
+	CreateMany(ctx context.Context, dtos []CreateTaskDTO) ([]TaskResponseDTO, error)
+	ExistingExternalUIDs(ctx context.Context, uids []string) (map[string]bool, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*TaskResponseDTO, error)
 	GetAllByUserID(ctx context.Context) ([]TaskResponseDTO, error)
+	List(ctx context.Context, filter TaskFilter) (*TaskPageDTO, error)
 	GetByCollection(ctx context.Context, collectionID uuid.UUID) ([]TaskResponseDTO, error)
+	GetOrderedByCollection(ctx context.Context, collectionID uuid.UUID) ([]TaskResponseDTO, error)
+	GetOccurrences(ctx context.Context, from, to time.Time, loc *time.Location) ([]OccurrenceResponseDTO, error)
 	UpdateStatus(ctx context.Context, id uuid.UUID, status Status) (*TaskResponseDTO, error)
+	UpdateOccurrenceStatus(ctx context.Context, id uuid.UUID, occurrenceDate time.Time, status Status) (*OccurrenceResponseDTO, error)
 	Update(ctx context.Context, id uuid.UUID, dto *UpdateTaskDTO) (*TaskResponseDTO, error)
 	Delete(ctx context.Context, id uuid.UUID) error
+	GetTrash(ctx context.Context) ([]TaskResponseDTO, error)
+	Restore(ctx context.Context, id uuid.UUID) (*TaskResponseDTO, error)
+	PurgeTrash(ctx context.Context, before time.Time) (int64, error)
+
+	GetSubtasks(ctx context.Context, id uuid.UUID) ([]TaskResponseDTO, error)
+	CreateSubtask(ctx context.Context, parentID uuid.UUID, dto *CreateSubtaskDTO) (*TaskResponseDTO, error)
+	ReorderSubtasks(ctx context.Context, parentID uuid.UUID, dto *ReorderSubtasksDTO) ([]TaskResponseDTO, error)
+
+	Move(ctx context.Context, id uuid.UUID, dto *MoveTaskDTO) (*TaskResponseDTO, error)
+	Batch(ctx context.Context, dto *BatchRequestDTO) (*BatchResponseDTO, error)
+	QuickAdd(ctx context.Context, dto *QuickAddDTO) (*QuickAddResponseDTO, error)
+
+	GetOverdue(ctx context.Context) ([]TaskResponseDTO, error)
+	GetRolloverSettings(ctx context.Context) (*RolloverSettingsResponseDTO, error)
+	UpdateRolloverSettings(ctx context.Context, dto *UpdateRolloverSettingsDTO) (*RolloverSettingsResponseDTO, error)
+	GetRolloverLogs(ctx context.Context, limit int) ([]RolloverLogResponseDTO, error)
+	ApplyRollover(ctx context.Context, now time.Time) (int, error)
+
+	StartTimer(ctx context.Context, id uuid.UUID) (*TimeEntryResponseDTO, error)
+	StopTimer(ctx context.Context, id uuid.UUID) (*TimeEntryResponseDTO, error)
+	GetRunningTimer(ctx context.Context) (*TimeEntryResponseDTO, error)
+	GetTimeEntries(ctx context.Context, id uuid.UUID) ([]TimeEntryResponseDTO, error)
+	CreateTimeEntry(ctx context.Context, id uuid.UUID, dto *CreateTimeEntryDTO) (*TimeEntryResponseDTO, error)
+	DeleteTimeEntry(ctx context.Context, id, entryID uuid.UUID) error
+
+	GetComments(ctx context.Context, id uuid.UUID) ([]CommentResponseDTO, error)
+	CreateComment(ctx context.Context, id uuid.UUID, dto *CommentDTO) (*CommentResponseDTO, error)
+	UpdateComment(ctx context.Context, id, commentID uuid.UUID, dto *CommentDTO) (*CommentResponseDTO, error)
+	DeleteComment(ctx context.Context, id, commentID uuid.UUID) error
+
+	GetDependencies(ctx context.Context, id uuid.UUID) ([]TaskResponseDTO, error)
+	AddDependency(ctx context.Context, id uuid.UUID, dto *AddDependencyDTO) ([]TaskResponseDTO, error)
+	RemoveDependency(ctx context.Context, id, blockedByID uuid.UUID) error
 }
 
+const (
+	maxOccurrenceRange = 366 * 24 * time.Hour
+	maxRollUpDepth     = 32
+	maxBatchSize       = 500
+	defaultLogSize     = 50
+	maxLogSize         = 200
+	maxCommentLength   = 10000
+)
+
+// errBatchRolledBack aborts the transaction of an atomic batch in which an
+// item failed.
+var errBatchRolledBack = errors.New("batch rolled back")
+
 type service struct {
-	repository Repository
+	repository  Repository
+	collections collections.Repository
+	locations   auth.Locator
 }
 
-func NewService(repository Repository) Service {
-	return &service{repository: repository}
+func NewService(repository Repository, collectionRepository collections.Repository, locator auth.Locator) Service {
+	return &service{repository: repository, collections: collectionRepository, locations: locator}
 }
 
 func (s *service) Create(ctx context.Context, dto *CreateTaskDTO) (*TaskResponseDTO, error) {
@@ -32,19 +94,19 @@ func (s *service) Create(ctx context.Context, dto *CreateTaskDTO) (*TaskResponse
 		return nil, ErrUnauthorized
 	}
 
-	if !dto.Status.IsValid() {
-		return nil, ErrInvalidTaskStatus
-	}
-	if !dto.Priority.IsValid() {
-		return nil, ErrInvalidTaskPriority
+	wf, err := s.workflowFor(ctx, userID, dto.CollectionID)
+	if err != nil {
+		return nil, err
 	}
 
-	task := dto.ToEntity()
-	task.UserID = userID
+	task, err := s.newTask(userID, dto, wf)
+	if err != nil {
+		return nil, err
+	}
 
-	if task.Status == Done {
-		now := time.Now()
-		task.FinishedAt = &now
+	task.BoardPosition, err = s.repository.NextBoardPosition(ctx, userID, task.CollectionID, task.Status)
+	if err != nil {
+		return nil, err
 	}
 
 	if err := s.repository.Create(ctx, task); err != nil {
@@ -55,6 +117,82 @@ func (s *service) Create(ctx context.Context, dto *CreateTaskDTO) (*TaskResponse
 	return &response, nil
 }
 
+// CreateMany validates every DTO first and then creates all tasks in a
+// single transaction, so either the whole batch is stored or none of it.
+func (s *service) CreateMany(ctx context.Context, dtos []CreateTaskDTO) ([]TaskResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	workflows := make(map[uuid.UUID]*workflow)
+	tasks := make([]Task, len(dtos))
+	for i := range dtos {
+		key := uuid.Nil
+		if dtos[i].CollectionID != nil {
+			key = *dtos[i].CollectionID
+		}
+		wf, ok := workflows[key]
+		if !ok {
+			wf, err = s.workflowFor(ctx, userID, dtos[i].CollectionID)
+			if err != nil {
+				return nil, err
+			}
+			workflows[key] = wf
+		}
+
+		task, err := s.newTask(userID, &dtos[i], wf)
+		if err != nil {
+			return nil, err
+		}
+		tasks[i] = *task
+	}
+
+	err = s.repository.Transaction(ctx, func(repo Repository) error {
+		for i := range tasks {
+			position, err := repo.NextBoardPosition(ctx, userID, tasks[i].CollectionID, tasks[i].Status)
+			if err != nil {
+				return err
+			}
+			tasks[i].BoardPosition = position
+
+			if err := repo.Create(ctx, &tasks[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if isUniqueViolation(err) {
+		// Another import stored one of the UIDs after they were checked.
+		return nil, ErrAlreadyImported
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return ToResponseList(tasks), nil
+}
+
+// ExistingExternalUIDs reports which of the given external (e.g. iCalendar)
+// UIDs were already imported by the user.
+func (s *service) ExistingExternalUIDs(ctx context.Context, uids []string) (map[string]bool, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	found, err := s.repository.FindExternalUIDs(ctx, userID, uids)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]bool, len(found))
+	for _, uid := range found {
+		existing[uid] = true
+	}
+	return existing, nil
+}
+
 func (s *service) GetByID(ctx context.Context, id uuid.UUID) (*TaskResponseDTO, error) {
 	userID, err := middlewares.GetUserIDFromContext(ctx)
 	if err != nil {
@@ -66,8 +204,7 @@ func (s *service) GetByID(ctx context.Context, id uuid.UUID) (*TaskResponseDTO,
 		return nil, ErrTaskNotFound
 	}
 
-	response := ToResponse(*task)
-	return &response, nil
+	return s.toResponse(ctx, userID, *task)
 }
 
 func (s *service) GetAllByUserID(ctx context.Context) ([]TaskResponseDTO, error) {
@@ -81,7 +218,58 @@ func (s *service) GetAllByUserID(ctx context.Context) ([]TaskResponseDTO, error)
 		return nil, err
 	}
 
-	return ToResponseList(tasks), nil
+	return s.toResponseList(ctx, userID, tasks)
+}
+
+func (s *service) List(ctx context.Context, filter TaskFilter) (*TaskPageDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	if filter.Status != nil && *filter.Status == "" {
+		return nil, ErrInvalidTaskStatus
+	}
+	if filter.Priority != nil && !filter.Priority.IsValid() {
+		return nil, ErrInvalidTaskPriority
+	}
+	if filter.Sort == "" {
+		filter.Sort = SortByStartTime
+	}
+	if !filter.Sort.IsValid() {
+		return nil, ErrInvalidSortField
+	}
+	if filter.Limit < 0 || filter.Limit > maxPageSize {
+		return nil, ErrInvalidPageSize
+	}
+	if filter.Limit == 0 && filter.Cursor != nil {
+		filter.Limit = defaultPageSize
+	}
+	if filter.Cursor != nil && !filter.Cursor.matches(filter.Sort, filter.Descending) {
+		return nil, ErrInvalidCursor
+	}
+	if filter.From != nil && filter.To != nil && !filter.To.After(*filter.From) {
+		return nil, ErrInvalidDateRange
+	}
+
+	tasks, err := s.repository.List(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &TaskPageDTO{}
+	if filter.Limit > 0 && len(tasks) > filter.Limit {
+		tasks = tasks[:filter.Limit]
+		next := NewCursor(tasks[len(tasks)-1], filter.Sort, filter.Descending).Encode()
+		page.NextCursor = &next
+	}
+
+	page.Items, err = s.toResponseList(ctx, userID, tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
 }
 
 func (s *service) GetByCollection(ctx context.Context, collectionID uuid.UUID) ([]TaskResponseDTO, error) {
@@ -95,17 +283,132 @@ func (s *service) GetByCollection(ctx context.Context, collectionID uuid.UUID) (
 		return nil, err
 	}
 
-	return ToResponseList(tasks), nil
+	return s.toResponseList(ctx, userID, tasks)
 }
 
-func (s *service) UpdateStatus(ctx context.Context, id uuid.UUID, status Status) (*TaskResponseDTO, error) {
+// GetOrderedByCollection returns the tasks of a collection sorted so that
+// every task comes after the tasks blocking it.
+func (s *service) GetOrderedByCollection(ctx context.Context, collectionID uuid.UUID) ([]TaskResponseDTO, error) {
 	userID, err := middlewares.GetUserIDFromContext(ctx)
 	if err != nil {
 		return nil, ErrUnauthorized
 	}
 
-	if !status.IsValid() {
-		return nil, ErrInvalidTaskStatus
+	tasks, err := s.repository.GetByCollectionID(ctx, userID, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].StartTime.Before(tasks[j].StartTime)
+	})
+
+	ids := make([]uuid.UUID, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.ID
+	}
+
+	edges, err := s.repository.GetDependencyEdges(ctx, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponseList(ctx, userID, topologicalOrder(tasks, edges))
+}
+
+// GetOccurrences expands every task of the user into its occurrences within
+// [from, to). Non-recurring tasks produce a single occurrence whenever they
+// overlap the range; recurring ones are expanded in loc or, when it is nil,
+// in the user's time zone.
+func (s *service) GetOccurrences(ctx context.Context, from, to time.Time, loc *time.Location) ([]OccurrenceResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	if !to.After(from) || to.Sub(from) > maxOccurrenceRange {
+		return nil, ErrInvalidDateRange
+	}
+
+	tasks, err := s.repository.GetInRange(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var recurringIDs []uuid.UUID
+	for _, t := range tasks {
+		if t.RecurrenceRule != nil {
+			recurringIDs = append(recurringIDs, t.ID)
+		}
+	}
+
+	if loc == nil {
+		if loc, err = s.locations.Location(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Occurrences that started before from but are still running overlap the
+	// range too, so their overrides are looked up from the earliest start.
+	expanded := make(map[uuid.UUID][]time.Time, len(recurringIDs))
+	earliest := from
+	for _, t := range tasks {
+		if t.RecurrenceRule == nil {
+			continue
+		}
+
+		rule, err := ParseRecurrenceRule(*t.RecurrenceRule)
+		if err != nil {
+			continue
+		}
+
+		dates := rule.Between(t.StartTime.In(loc), t.duration(), from, to)
+		for i := range dates {
+			dates[i] = dates[i].UTC()
+			if dates[i].Before(earliest) {
+				earliest = dates[i]
+			}
+		}
+		expanded[t.ID] = dates
+	}
+
+	overrides, err := s.repository.GetOccurrences(ctx, userID, recurringIDs, earliest, to)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]TaskOccurrence, len(overrides))
+	for _, o := range overrides {
+		byKey[occurrenceKey(o.TaskID, o.OccurrenceDate)] = o
+	}
+
+	occurrences := make([]OccurrenceResponseDTO, 0, len(tasks))
+	for _, t := range tasks {
+		if t.RecurrenceRule == nil {
+			occurrences = append(occurrences, toOccurrenceResponse(t, t.StartTime, nil))
+			continue
+		}
+
+		for _, date := range expanded[t.ID] {
+			var override *TaskOccurrence
+			if o, ok := byKey[occurrenceKey(t.ID, date)]; ok {
+				override = &o
+			}
+			occurrences = append(occurrences, toOccurrenceResponse(t, date, override))
+		}
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].StartTime.Before(occurrences[j].StartTime)
+	})
+
+	return occurrences, nil
+}
+
+func (s *service) UpdateStatus(ctx context.Context, id uuid.UUID, status Status) (*TaskResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
 	}
 
 	task, err := s.repository.GetByID(ctx, id, userID)
@@ -113,13 +416,96 @@ func (s *service) UpdateStatus(ctx context.Context, id uuid.UUID, status Status)
 		return nil, ErrTaskNotFound
 	}
 
-	s.applyStatusChange(task, status)
+	wf, err := s.workflowFor(ctx, userID, task.CollectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.transition(ctx, task, status, wf); err != nil {
+		return nil, err
+	}
 
 	if err := s.repository.Update(ctx, task); err != nil {
 		return nil, err
 	}
 
-	response := ToResponse(*task)
+	if err := s.rollUp(ctx, userID, task.ParentID); err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(ctx, userID, *task)
+}
+
+// UpdateOccurrenceStatus changes the status of a single occurrence of a
+// recurring task, leaving the rest of the series untouched.
+func (s *service) UpdateOccurrenceStatus(ctx context.Context, id uuid.UUID, occurrenceDate time.Time, status Status) (*OccurrenceResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	task, err := s.repository.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, ErrTaskNotFound
+	}
+
+	if task.RecurrenceRule == nil {
+		return nil, ErrTaskNotRecurring
+	}
+
+	rule, err := ParseRecurrenceRule(*task.RecurrenceRule)
+	if err != nil {
+		return nil, ErrInvalidRecurrence
+	}
+	loc, err := s.locations.Location(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !rule.Includes(task.StartTime.In(loc), occurrenceDate) {
+		return nil, ErrInvalidOccurrence
+	}
+
+	wf, err := s.workflowFor(ctx, userID, task.CollectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err = wf.resolve(status)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.repository.GetOccurrence(ctx, userID, task.ID, occurrenceDate)
+	if err != nil {
+		return nil, err
+	}
+	from := task.Status
+	if current != nil {
+		from = current.Status
+	}
+	if !wf.canTransition(from, status) {
+		return nil, ErrInvalidTransition
+	}
+
+	occurrence := &TaskOccurrence{
+		TaskID:         task.ID,
+		UserID:         userID,
+		OccurrenceDate: occurrenceDate,
+		Status:         status,
+	}
+	if wf.isTerminal(status) {
+		now := time.Now()
+		occurrence.FinishedAt = &now
+		if current != nil && current.Status == status && current.FinishedAt != nil {
+			occurrence.FinishedAt = current.FinishedAt
+		}
+	}
+
+	if err := s.repository.SaveOccurrence(ctx, occurrence); err != nil {
+		return nil, err
+	}
+
+	response := toOccurrenceResponse(*task, occurrenceDate, occurrence)
 	return &response, nil
 }
 
@@ -134,16 +520,39 @@ func (s *service) Update(ctx context.Context, id uuid.UUID, dto *UpdateTaskDTO)
 		return nil, ErrTaskNotFound
 	}
 
+	previousCollection := task.CollectionID
 	if err := s.applyUpdates(task, dto); err != nil {
 		return nil, err
 	}
 
+	moved := !sameCollection(previousCollection, task.CollectionID)
+	if dto.Status != nil || moved {
+		wf, err := s.workflowFor(ctx, userID, task.CollectionID)
+		if err != nil {
+			return nil, err
+		}
+
+		if moved {
+			err = s.rehome(ctx, task, dto.Status, wf)
+		} else {
+			err = s.transition(ctx, task, *dto.Status, wf)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if err := s.repository.Update(ctx, task); err != nil {
 		return nil, err
 	}
 
-	response := ToResponse(*task)
-	return &response, nil
+	if dto.Status != nil {
+		if err := s.rollUp(ctx, userID, task.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.toResponse(ctx, userID, *task)
 }
 
 func (s *service) Delete(ctx context.Context, id uuid.UUID) error {
@@ -152,51 +561,1321 @@ func (s *service) Delete(ctx context.Context, id uuid.UUID) error {
 		return ErrUnauthorized
 	}
 
-	_, err = s.repository.GetByID(ctx, id, userID)
+	task, err := s.repository.GetByID(ctx, id, userID)
 	if err != nil {
 		return ErrTaskNotFound
 	}
 
-	return s.repository.Delete(ctx, id, userID)
+	if err := s.repository.Delete(ctx, id, userID); err != nil {
+		return err
+	}
+
+	return s.rollUp(ctx, userID, task.ParentID)
 }
 
-func (s *service) applyUpdates(task *Task, dto *UpdateTaskDTO) error {
-	if dto.Title != nil {
-		task.Title = *dto.Title
+func (s *service) GetTrash(ctx context.Context) ([]TaskResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
 	}
-	if dto.Description != nil {
-		task.Description = dto.Description
+
+	tasks, err := s.repository.GetTrash(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
-	if dto.Status != nil {
-		if !dto.Status.IsValid() {
-			return ErrInvalidTaskStatus
-		}
-		s.applyStatusChange(task, *dto.Status)
+
+	return s.toResponseList(ctx, userID, tasks)
+}
+
+// Restore takes a task and the subtasks deleted with it out of the trash.
+// A subtask can only be restored once its parent is back.
+func (s *service) Restore(ctx context.Context, id uuid.UUID) (*TaskResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
 	}
-	if dto.Priority != nil {
-		if !dto.Priority.IsValid() {
-			return ErrInvalidTaskPriority
-		}
-		task.Priority = *dto.Priority
+
+	task, err := s.repository.GetDeletedByID(ctx, id, userID)
+	if err != nil {
+		return nil, ErrTaskNotFound
 	}
-	if dto.CollectionID != nil {
-		task.CollectionID = dto.CollectionID
+
+	if task.ParentID != nil {
+		if _, err := s.repository.GetByID(ctx, *task.ParentID, userID); err != nil {
+			return nil, ErrParentDeleted
+		}
 	}
-	if dto.StartTime != nil {
-		task.StartTime = *dto.StartTime
+
+	if err := s.repository.Restore(ctx, task); err != nil {
+		return nil, err
 	}
-	if dto.EndTime != nil {
-		task.EndTime = dto.EndTime
+
+	if err := s.rollUp(ctx, userID, task.ParentID); err != nil {
+		return nil, err
 	}
-	return nil
+
+	return s.GetByID(ctx, id)
 }
 
-func (s *service) applyStatusChange(task *Task, newStatus Status) {
-	if newStatus == Done && task.Status != Done {
-		now := time.Now()
-		task.FinishedAt = &now
-	} else if newStatus != Done {
+// PurgeTrash permanently removes every task deleted before the given
+// instant. It runs as a scheduled job, outside of any user request.
+func (s *service) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	return s.repository.PurgeDeleted(ctx, before)
+}
+
+func (s *service) GetSubtasks(ctx context.Context, id uuid.UUID) ([]TaskResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	if _, err := s.repository.GetByID(ctx, id, userID); err != nil {
+		return nil, ErrTaskNotFound
+	}
+
+	subtasks, err := s.repository.GetSubtasks(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponseList(ctx, userID, subtasks)
+}
+
+func (s *service) CreateSubtask(ctx context.Context, parentID uuid.UUID, dto *CreateSubtaskDTO) (*TaskResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	parent, err := s.repository.GetByID(ctx, parentID, userID)
+	if err != nil {
+		return nil, ErrTaskNotFound
+	}
+
+	if parent.RecurrenceRule != nil {
+		return nil, ErrSubtaskRecurrence
+	}
+
+	task := dto.ToEntity(parent)
+	if !task.Priority.IsValid() {
+		return nil, ErrInvalidTaskPriority
+	}
+
+	wf, err := s.workflowFor(ctx, userID, task.CollectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := wf.resolve(task.Status)
+	if err != nil {
+		return nil, err
+	}
+	s.applyStatusChange(task, status, wf)
+
+	position, err := s.repository.NextSubtaskPosition(ctx, parentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	task.Position = position
+
+	task.BoardPosition, err = s.repository.NextBoardPosition(ctx, userID, task.CollectionID, task.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.Create(ctx, task); err != nil {
+		return nil, err
+	}
+
+	if err := s.rollUp(ctx, userID, task.ParentID); err != nil {
+		return nil, err
+	}
+
+	response := ToResponse(*task)
+	return &response, nil
+}
+
+func (s *service) ReorderSubtasks(ctx context.Context, parentID uuid.UUID, dto *ReorderSubtasksDTO) ([]TaskResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	if _, err := s.repository.GetByID(ctx, parentID, userID); err != nil {
+		return nil, ErrTaskNotFound
+	}
+
+	subtasks, err := s.repository.GetSubtasks(ctx, parentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(dto.IDs) != len(subtasks) {
+		return nil, ErrInvalidSubtaskOrder
+	}
+
+	pending := make(map[uuid.UUID]bool, len(subtasks))
+	for _, t := range subtasks {
+		pending[t.ID] = true
+	}
+	for _, id := range dto.IDs {
+		if !pending[id] {
+			return nil, ErrInvalidSubtaskOrder
+		}
+		delete(pending, id)
+	}
+
+	if err := s.repository.UpdatePositions(ctx, userID, dto.IDs); err != nil {
+		return nil, err
+	}
+
+	subtasks, err = s.repository.GetSubtasks(ctx, parentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponseList(ctx, userID, subtasks)
+}
+
+// Move drags a task on the kanban board: it changes the task's column when
+// needed and places it between the AfterID and BeforeID cards of that
+// column. Without neighbours the task goes to the bottom of the column.
+func (s *service) Move(ctx context.Context, id uuid.UUID, dto *MoveTaskDTO) (*TaskResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	task, err := s.repository.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, ErrTaskNotFound
+	}
+
+	wf, err := s.workflowFor(ctx, userID, task.CollectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.transition(ctx, task, dto.Status, wf); err != nil {
+		return nil, err
+	}
+
+	if dto.AfterID != nil || dto.BeforeID != nil {
+		position, err := s.boardPositionBetween(ctx, task, dto.AfterID, dto.BeforeID)
+		if err != nil {
+			return nil, err
+		}
+		task.BoardPosition = position
+	}
+
+	if err := s.repository.Update(ctx, task); err != nil {
+		return nil, err
+	}
+
+	if err := s.rollUp(ctx, userID, task.ParentID); err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(ctx, userID, *task)
+}
+
+func (s *service) GetOverdue(ctx context.Context) ([]TaskResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	tasks, err := s.repository.GetOverdue(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponseList(ctx, userID, tasks)
+}
+
+func (s *service) GetRolloverSettings(ctx context.Context) (*RolloverSettingsResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	settings, err := s.repository.GetRolloverSettings(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &RolloverSettingsResponseDTO{Policy: RolloverLeave}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &RolloverSettingsResponseDTO{Policy: settings.Policy}, nil
+}
+
+func (s *service) UpdateRolloverSettings(ctx context.Context, dto *UpdateRolloverSettingsDTO) (*RolloverSettingsResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	if !dto.Policy.IsValid() {
+		return nil, ErrInvalidRollover
+	}
+
+	settings := &RolloverSettings{UserID: userID, Policy: dto.Policy}
+	if err := s.repository.SaveRolloverSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+
+	return &RolloverSettingsResponseDTO{Policy: settings.Policy}, nil
+}
+
+func (s *service) GetRolloverLogs(ctx context.Context, limit int) ([]RolloverLogResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	if limit <= 0 {
+		limit = defaultLogSize
+	}
+	if limit > maxLogSize {
+		limit = maxLogSize
+	}
+
+	logs, err := s.repository.GetRolloverLogs(ctx, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToRolloverLogResponseList(logs), nil
+}
+
+// ApplyRollover is run by the scheduled job for every user with an active
+// policy. Tasks that ended before the start of the user's current day are
+// moved to today or to the next day without open tasks, keeping their
+// local times, and each move is logged. It returns how many tasks moved.
+func (s *service) ApplyRollover(ctx context.Context, now time.Time) (int, error) {
+	settings, err := s.repository.GetActiveRolloverSettings(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	moved := 0
+	var firstErr error
+	for _, setting := range settings {
+		n, err := s.rolloverUser(ctx, setting, now)
+		moved += n
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return moved, firstErr
+}
+
+func (s *service) rolloverUser(ctx context.Context, settings RolloverSettings, now time.Time) (int, error) {
+	loc, err := s.locations.Location(ctx, settings.UserID)
+	if err != nil {
+		return 0, err
+	}
+	today := startOfDay(now, loc)
+
+	overdue, err := s.repository.GetOverdue(ctx, settings.UserID, today)
+	if err != nil || len(overdue) == 0 {
+		return 0, err
+	}
+
+	var counts map[string]int
+	if settings.Policy == RolloverNextFreeDay {
+		counts, err = s.repository.CountOpenTasksPerDay(ctx, settings.UserID, today, today.AddDate(0, 0, maxFreeDaySearch), loc)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = s.repository.Transaction(ctx, func(repo Repository) error {
+		for i := range overdue {
+			task := &overdue[i]
+			entry := &RolloverLog{
+				UserID:        settings.UserID,
+				TaskID:        task.ID,
+				Policy:        settings.Policy,
+				PreviousStart: task.StartTime,
+				PreviousEnd:   task.EndTime,
+			}
+
+			target := today
+			if settings.Policy == RolloverNextFreeDay {
+				target = nextFreeDay(today, counts)
+			}
+			shiftTask(task, daysBetween(task.EndTime.In(loc), target), loc)
+
+			entry.NewStart = task.StartTime
+			entry.NewEnd = task.EndTime
+
+			if err := repo.Update(ctx, task); err != nil {
+				return err
+			}
+			if err := repo.CreateRolloverLog(ctx, entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(overdue), nil
+}
+
+// QuickAdd parses a free-text task and returns the preview of the task it
+// describes, creating it when dto.Commit is set. The #collection tag is
+// matched against the user's collection titles ignoring case and accents.
+func (s *service) QuickAdd(ctx context.Context, dto *QuickAddDTO) (*QuickAddResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	var loc *time.Location
+	if dto.Timezone != "" {
+		if loc, err = time.LoadLocation(dto.Timezone); err != nil {
+			return nil, ErrInvalidTimezone
+		}
+	} else if loc, err = s.locations.Location(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	parsed, err := ParseQuickAdd(dto.Text, time.Now().In(loc))
+	if err != nil {
+		return nil, err
+	}
+
+	preview := QuickAddPreviewDTO{
+		Title:     parsed.Title,
+		StartTime: parsed.StartTime,
+		Priority:  parsed.Priority,
+		Matched:   parsed.Matched,
+		Warnings:  []string{},
+	}
+	if preview.Matched == nil {
+		preview.Matched = []string{}
+	}
+
+	if parsed.Collection != "" {
+		collections, err := s.collections.GetAllByUserID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		wanted := accentReplacer.Replace(strings.ToLower(parsed.Collection))
+		for _, c := range collections {
+			if accentReplacer.Replace(strings.ToLower(c.Title)) == wanted {
+				id, title := c.ID, c.Title
+				preview.CollectionID = &id
+				preview.CollectionTitle = &title
+				break
+			}
+		}
+		if preview.CollectionID == nil {
+			preview.Warnings = append(preview.Warnings, fmt.Sprintf("collection %q not found", parsed.Collection))
+		}
+	}
+
+	result := &QuickAddResponseDTO{Preview: preview}
+	if !dto.Commit {
+		return result, nil
+	}
+
+	result.Task, err = s.Create(ctx, &CreateTaskDTO{
+		Title:        preview.Title,
+		Status:       Pending,
+		Priority:     preview.Priority,
+		CollectionID: preview.CollectionID,
+		StartTime:    preview.StartTime,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Batch applies one operation to many tasks inside a single transaction.
+// Items failing validation (not found, invalid transition, blocked...) are
+// reported individually and skipped, unless the batch is atomic, in which
+// case nothing is committed. Database errors always abort the batch.
+func (s *service) Batch(ctx context.Context, dto *BatchRequestDTO) (*BatchResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	if err := s.validateBatch(ctx, userID, dto); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uuid.UUID]bool, len(dto.IDs))
+	result := &BatchResponseDTO{Operation: dto.Operation, Results: make([]BatchItemResultDTO, 0, len(dto.IDs))}
+	var updated []Task
+
+	err = s.repository.Transaction(ctx, func(repo Repository) error {
+		tx := &service{repository: repo, collections: s.collections, locations: s.locations}
+
+		for _, id := range dto.IDs {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			task, err := tx.applyBatchItem(ctx, userID, id, dto)
+			if err != nil && !isItemError(err) {
+				return err
+			}
+
+			item := BatchItemResultDTO{ID: id, Success: err == nil}
+			if err != nil {
+				item.Error = err.Error()
+				result.Failed++
+			} else {
+				result.Succeeded++
+				if task != nil {
+					updated = append(updated, *task)
+				}
+			}
+			result.Results = append(result.Results, item)
+		}
+
+		if dto.Atomic && result.Failed > 0 {
+			return errBatchRolledBack
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBatchRolledBack) {
+		return nil, err
+	}
+	result.Committed = err == nil
+
+	if result.Committed && len(updated) > 0 {
+		responses, err := s.toResponseList(ctx, userID, updated)
+		if err != nil {
+			return nil, err
+		}
+		byID := make(map[uuid.UUID]*TaskResponseDTO, len(responses))
+		for i := range responses {
+			byID[responses[i].ID] = &responses[i]
+		}
+		for i := range result.Results {
+			result.Results[i].Task = byID[result.Results[i].ID]
+		}
+	}
+
+	return result, nil
+}
+
+func (s *service) validateBatch(ctx context.Context, userID uuid.UUID, dto *BatchRequestDTO) error {
+	if len(dto.IDs) == 0 || len(dto.IDs) > maxBatchSize || !dto.Operation.IsValid() {
+		return ErrInvalidBatch
+	}
+
+	switch dto.Operation {
+	case BatchSetStatus:
+		if dto.Status == nil || *dto.Status == "" {
+			return ErrInvalidBatch
+		}
+	case BatchSetPriority:
+		if dto.Priority == nil {
+			return ErrInvalidBatch
+		}
+		if !dto.Priority.IsValid() {
+			return ErrInvalidTaskPriority
+		}
+	case BatchMoveCollection:
+		if dto.CollectionID != nil {
+			if _, err := s.collections.GetByID(ctx, *dto.CollectionID, userID); err != nil {
+				return ErrCollectionNotFound
+			}
+		}
+	case BatchShiftDates:
+		if dto.Days == nil || *dto.Days == 0 {
+			return ErrInvalidBatch
+		}
+	}
+
+	return nil
+}
+
+// applyBatchItem applies the batch operation to a single task and returns
+// the updated task, or nil when it was deleted.
+func (s *service) applyBatchItem(ctx context.Context, userID, id uuid.UUID, dto *BatchRequestDTO) (*Task, error) {
+	task, err := s.repository.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, ErrTaskNotFound
+	}
+
+	switch dto.Operation {
+	case BatchDelete:
+		if err := s.repository.Delete(ctx, id, userID); err != nil {
+			return nil, err
+		}
+		return nil, s.rollUp(ctx, userID, task.ParentID)
+
+	case BatchSetStatus:
+		wf, err := s.workflowFor(ctx, userID, task.CollectionID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.transition(ctx, task, *dto.Status, wf); err != nil {
+			return nil, err
+		}
+
+	case BatchSetPriority:
+		task.Priority = *dto.Priority
+
+	case BatchMoveCollection:
+		if sameCollection(task.CollectionID, dto.CollectionID) {
+			return task, nil
+		}
+		task.CollectionID = dto.CollectionID
+		wf, err := s.workflowFor(ctx, userID, task.CollectionID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.rehome(ctx, task, nil, wf); err != nil {
+			return nil, err
+		}
+
+	case BatchShiftDates:
+		task.StartTime = task.StartTime.AddDate(0, 0, *dto.Days)
+		if task.EndTime != nil {
+			end := task.EndTime.AddDate(0, 0, *dto.Days)
+			task.EndTime = &end
+		}
+		// Moving the anchor moves the whole series, so the overrides keyed
+		// by occurrence date have to follow it.
+		if task.RecurrenceRule != nil {
+			if err := s.repository.ShiftOccurrences(ctx, userID, task.ID, *dto.Days); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := s.repository.Update(ctx, task); err != nil {
+		return nil, err
+	}
+
+	if dto.Operation == BatchSetStatus {
+		if err := s.rollUp(ctx, userID, task.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	return task, nil
+}
+
+// isItemError reports whether err concerns a single batch item rather than
+// the batch as a whole.
+func isItemError(err error) bool {
+	switch err {
+	case ErrTaskNotFound, ErrInvalidTaskStatus, ErrInvalidTransition, ErrTaskBlocked:
+		return true
+	default:
+		return false
+	}
+}
+
+// boardPositionBetween returns a position strictly between the two
+// neighbours. When they are too close to split, the column is renumbered
+// once and the neighbours are read again.
+func (s *service) boardPositionBetween(ctx context.Context, task *Task, afterID, beforeID *uuid.UUID) (float64, error) {
+	neighbour := func(id *uuid.UUID) (*Task, error) {
+		if id == nil {
+			return nil, nil
+		}
+		if *id == task.ID {
+			return nil, ErrInvalidBoardPosition
+		}
+		n, err := s.repository.GetByID(ctx, *id, task.UserID)
+		if err != nil || n.Status != task.Status || !sameCollection(n.CollectionID, task.CollectionID) {
+			return nil, ErrInvalidBoardPosition
+		}
+		return n, nil
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		after, err := neighbour(afterID)
+		if err != nil {
+			return 0, err
+		}
+		before, err := neighbour(beforeID)
+		if err != nil {
+			return 0, err
+		}
+
+		switch {
+		case after != nil && before != nil:
+			if gap := before.BoardPosition - after.BoardPosition; gap > minBoardGap {
+				return after.BoardPosition + gap/2, nil
+			}
+		case after != nil:
+			return after.BoardPosition + boardPositionStep, nil
+		case before != nil:
+			return before.BoardPosition - boardPositionStep, nil
+		}
+
+		if attempt == 0 {
+			if err := s.repository.RebalanceBoard(ctx, task.UserID, task.CollectionID, task.Status); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return 0, ErrInvalidBoardPosition
+}
+
+// StartTimer opens a new time entry on the task. Only one timer may run per
+// user, so a running timer has to be stopped first.
+func (s *service) StartTimer(ctx context.Context, id uuid.UUID) (*TimeEntryResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	if _, err := s.repository.GetByID(ctx, id, userID); err != nil {
+		return nil, ErrTaskNotFound
+	}
+
+	if _, err := s.repository.GetRunningTimeEntry(ctx, userID); err == nil {
+		return nil, ErrTimerRunning
+	}
+
+	entry := &TimeEntry{
+		TaskID:    id,
+		UserID:    userID,
+		StartedAt: time.Now(),
+	}
+
+	// The check above can race with another start; the partial unique index
+	// on running entries then rejects the second one.
+	err = s.repository.CreateTimeEntry(ctx, entry)
+	if isUniqueViolation(err) {
+		return nil, ErrTimerRunning
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response := ToTimeEntryResponse(*entry)
+	return &response, nil
+}
+
+func (s *service) StopTimer(ctx context.Context, id uuid.UUID) (*TimeEntryResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	entry, err := s.repository.GetRunningTimeEntry(ctx, userID)
+	if err != nil || entry.TaskID != id {
+		return nil, ErrNoRunningTimer
+	}
+
+	now := time.Now()
+	entry.EndedAt = &now
+
+	if err := s.repository.UpdateTimeEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	response := ToTimeEntryResponse(*entry)
+	return &response, nil
+}
+
+// GetRunningTimer returns the user's running timer, or nil when none is.
+func (s *service) GetRunningTimer(ctx context.Context) (*TimeEntryResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	entry, err := s.repository.GetRunningTimeEntry(ctx, userID)
+	if err != nil {
+		return nil, nil
+	}
+
+	response := ToTimeEntryResponse(*entry)
+	return &response, nil
+}
+
+func (s *service) GetTimeEntries(ctx context.Context, id uuid.UUID) ([]TimeEntryResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	if _, err := s.repository.GetByID(ctx, id, userID); err != nil {
+		return nil, ErrTaskNotFound
+	}
+
+	entries, err := s.repository.GetTimeEntries(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToTimeEntryResponseList(entries), nil
+}
+
+// CreateTimeEntry records a finished session entered by hand.
+func (s *service) CreateTimeEntry(ctx context.Context, id uuid.UUID, dto *CreateTimeEntryDTO) (*TimeEntryResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	if _, err := s.repository.GetByID(ctx, id, userID); err != nil {
+		return nil, ErrTaskNotFound
+	}
+
+	if !dto.EndedAt.After(dto.StartedAt) || dto.EndedAt.After(time.Now()) {
+		return nil, ErrInvalidTimeEntry
+	}
+
+	endedAt := dto.EndedAt
+	entry := &TimeEntry{
+		TaskID:    id,
+		UserID:    userID,
+		StartedAt: dto.StartedAt,
+		EndedAt:   &endedAt,
+		Manual:    true,
+		Note:      dto.Note,
+	}
+
+	if err := s.repository.CreateTimeEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	response := ToTimeEntryResponse(*entry)
+	return &response, nil
+}
+
+func (s *service) DeleteTimeEntry(ctx context.Context, id, entryID uuid.UUID) error {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return ErrUnauthorized
+	}
+
+	entry, err := s.repository.GetTimeEntry(ctx, entryID, userID)
+	if err != nil || entry.TaskID != id {
+		return ErrTimeEntryNotFound
+	}
+
+	return s.repository.DeleteTimeEntry(ctx, entryID, userID)
+}
+
+func (s *service) GetComments(ctx context.Context, id uuid.UUID) ([]CommentResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	if _, err := s.repository.GetByID(ctx, id, userID); err != nil {
+		return nil, ErrTaskNotFound
+	}
+
+	comments, err := s.repository.GetComments(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToCommentResponseList(comments), nil
+}
+
+func (s *service) CreateComment(ctx context.Context, id uuid.UUID, dto *CommentDTO) (*CommentResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	if _, err := s.repository.GetByID(ctx, id, userID); err != nil {
+		return nil, ErrTaskNotFound
+	}
+
+	body, err := commentBody(dto.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &TaskComment{
+		TaskID: id,
+		UserID: userID,
+		Body:   body,
+	}
+
+	if err := s.repository.CreateComment(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	response := ToCommentResponse(*comment)
+	return &response, nil
+}
+
+func (s *service) UpdateComment(ctx context.Context, id, commentID uuid.UUID, dto *CommentDTO) (*CommentResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	comment, err := s.repository.GetComment(ctx, commentID, userID)
+	if err != nil || comment.TaskID != id {
+		return nil, ErrCommentNotFound
+	}
+
+	body, err := commentBody(dto.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if body != comment.Body {
+		now := time.Now()
+		comment.Body = body
+		comment.EditedAt = &now
+
+		if err := s.repository.UpdateComment(ctx, comment); err != nil {
+			return nil, err
+		}
+	}
+
+	response := ToCommentResponse(*comment)
+	return &response, nil
+}
+
+func (s *service) DeleteComment(ctx context.Context, id, commentID uuid.UUID) error {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return ErrUnauthorized
+	}
+
+	comment, err := s.repository.GetComment(ctx, commentID, userID)
+	if err != nil || comment.TaskID != id {
+		return ErrCommentNotFound
+	}
+
+	return s.repository.DeleteComment(ctx, commentID, userID)
+}
+
+// commentBody trims the markdown of a comment and checks its length. The
+// markdown is stored as sent and rendered by the client.
+func commentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" || utf8.RuneCountInString(body) > maxCommentLength {
+		return "", ErrInvalidComment
+	}
+	return body, nil
+}
+
+// GetDependencies lists the tasks blocking the given task.
+func (s *service) GetDependencies(ctx context.Context, id uuid.UUID) ([]TaskResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	if _, err := s.repository.GetByID(ctx, id, userID); err != nil {
+		return nil, ErrTaskNotFound
+	}
+
+	blockers, err := s.repository.GetBlockers(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponseList(ctx, userID, blockers)
+}
+
+// AddDependency marks the task as blocked by another task of the same user,
+// rejecting dependencies that would create a cycle.
+func (s *service) AddDependency(ctx context.Context, id uuid.UUID, dto *AddDependencyDTO) ([]TaskResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	if _, err := s.repository.GetByID(ctx, id, userID); err != nil {
+		return nil, ErrTaskNotFound
+	}
+	if _, err := s.repository.GetByID(ctx, dto.BlockedByID, userID); err != nil {
+		return nil, ErrTaskNotFound
+	}
+
+	err = s.repository.Transaction(ctx, func(repo Repository) error {
+		if err := repo.LockDependencies(ctx, userID); err != nil {
+			return err
+		}
+
+		edges, err := repo.GetDependencyEdges(ctx, userID, nil)
+		if err != nil {
+			return err
+		}
+		if createsCycle(edges, id, dto.BlockedByID) {
+			return ErrDependencyCycle
+		}
+
+		return repo.AddDependency(ctx, &TaskDependency{
+			TaskID:      id,
+			BlockedByID: dto.BlockedByID,
+			UserID:      userID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	blockers, err := s.repository.GetBlockers(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponseList(ctx, userID, blockers)
+}
+
+func (s *service) RemoveDependency(ctx context.Context, id, blockedByID uuid.UUID) error {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return ErrUnauthorized
+	}
+
+	removed, err := s.repository.RemoveDependency(ctx, id, blockedByID, userID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrDependencyNotFound
+	}
+
+	return nil
+}
+
+func (s *service) applyUpdates(task *Task, dto *UpdateTaskDTO) error {
+	if dto.Title != nil {
+		task.Title = *dto.Title
+	}
+	if dto.Description != nil {
+		task.Description = dto.Description
+	}
+	if dto.Priority != nil {
+		if !dto.Priority.IsValid() {
+			return ErrInvalidTaskPriority
+		}
+		task.Priority = *dto.Priority
+	}
+	if dto.CollectionID != nil {
+		task.CollectionID = dto.CollectionID
+	}
+	if dto.StartTime != nil {
+		task.StartTime = *dto.StartTime
+	}
+	if dto.EndTime != nil {
+		task.EndTime = dto.EndTime
+	}
+	if dto.Recurrence != nil {
+		rule, err := normalizeRecurrence(*dto.Recurrence)
+		if err != nil {
+			return err
+		}
+		if rule != nil && task.ParentID != nil {
+			return ErrSubtaskRecurrence
+		}
+		task.RecurrenceRule = rule
+	}
+	return nil
+}
+
+// applyStatusChange keeps FinishedAt in sync with the terminal columns of
+// the workflow: it is set when the task enters one and cleared when it
+// leaves them.
+func (s *service) applyStatusChange(task *Task, newStatus Status, wf *workflow) {
+	if wf.isTerminal(newStatus) {
+		if task.FinishedAt == nil {
+			now := time.Now()
+			task.FinishedAt = &now
+		}
+	} else {
 		task.FinishedAt = nil
 	}
 	task.Status = newStatus
 }
+
+// setStatus moves the task to the given column and puts it at the bottom of
+// that column on the board.
+func (s *service) setStatus(ctx context.Context, task *Task, status Status, wf *workflow) error {
+	s.applyStatusChange(task, status, wf)
+
+	position, err := s.repository.NextBoardPosition(ctx, task.UserID, task.CollectionID, status)
+	if err != nil {
+		return err
+	}
+	task.BoardPosition = position
+	return nil
+}
+
+// transition validates a user-requested status change against the
+// workflow and the task's blockers before applying it.
+func (s *service) transition(ctx context.Context, task *Task, status Status, wf *workflow) error {
+	status, err := wf.resolve(status)
+	if err != nil {
+		return err
+	}
+	if status == task.Status {
+		return nil
+	}
+	if !wf.canTransition(task.Status, status) {
+		return ErrInvalidTransition
+	}
+	if wf.isTerminal(status) && task.FinishedAt == nil {
+		blocked, err := s.isBlocked(ctx, task)
+		if err != nil {
+			return err
+		}
+		if blocked {
+			return ErrTaskBlocked
+		}
+	}
+	return s.setStatus(ctx, task, status, wf)
+}
+
+// rehome places a task that changed collection into the workflow of its new
+// collection, keeping its status when possible and otherwise falling back to
+// the initial or terminal column depending on whether it was finished.
+func (s *service) rehome(ctx context.Context, task *Task, requested *Status, wf *workflow) error {
+	status := task.Status
+	if requested != nil {
+		status = *requested
+	}
+
+	resolved, err := wf.resolve(status)
+	if err != nil {
+		if requested != nil {
+			return err
+		}
+		resolved = wf.initial()
+		if task.FinishedAt != nil {
+			resolved = wf.terminal()
+		}
+	}
+
+	return s.setStatus(ctx, task, resolved, wf)
+}
+
+// isBlocked reports whether any task blocking the given one is unfinished.
+func (s *service) isBlocked(ctx context.Context, task *Task) (bool, error) {
+	edges, err := s.repository.GetDependencyEdges(ctx, task.UserID, []uuid.UUID{task.ID})
+	if err != nil {
+		return false, err
+	}
+	for _, e := range edges {
+		if e.Pending {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// workflowFor loads the workflow of a collection, falling back to the
+// default one when the task has no collection or it defines no columns.
+func (s *service) workflowFor(ctx context.Context, userID uuid.UUID, collectionID *uuid.UUID) (*workflow, error) {
+	if collectionID == nil {
+		return defaultWorkflow, nil
+	}
+
+	columns, err := s.collections.GetWorkflow(ctx, *collectionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return defaultWorkflow, nil
+	}
+
+	return &workflow{columns: columns}, nil
+}
+
+func (s *service) newTask(userID uuid.UUID, dto *CreateTaskDTO, wf *workflow) (*Task, error) {
+	status, err := wf.resolve(dto.Status)
+	if err != nil {
+		return nil, err
+	}
+	if !dto.Priority.IsValid() {
+		return nil, ErrInvalidTaskPriority
+	}
+
+	task := dto.ToEntity()
+	task.UserID = userID
+	task.Status = status
+
+	if dto.Recurrence != nil {
+		rule, err := normalizeRecurrence(*dto.Recurrence)
+		if err != nil {
+			return nil, err
+		}
+		task.RecurrenceRule = rule
+	}
+
+	s.applyStatusChange(task, status, wf)
+
+	return task, nil
+}
+
+// rollUp walks up the ancestors of a task, moving each parent to a terminal
+// column once all of its subtasks are finished and reopening it when one of
+// them is not.
+func (s *service) rollUp(ctx context.Context, userID uuid.UUID, parentID *uuid.UUID) error {
+	for depth := 0; parentID != nil && depth < maxRollUpDepth; depth++ {
+		parent, err := s.repository.GetByID(ctx, *parentID, userID)
+		if err != nil {
+			return nil
+		}
+
+		counts, err := s.repository.CountSubtasks(ctx, userID, []uuid.UUID{parent.ID})
+		if err != nil {
+			return err
+		}
+
+		wf, err := s.workflowFor(ctx, userID, parent.CollectionID)
+		if err != nil {
+			return err
+		}
+
+		count, ok := counts[parent.ID]
+		if !ok {
+			return nil
+		}
+
+		switch {
+		case count.Done == count.Total && parent.FinishedAt == nil:
+			blocked, err := s.isBlocked(ctx, parent)
+			if err != nil {
+				return err
+			}
+			if blocked {
+				return nil
+			}
+			if err := s.setStatus(ctx, parent, wf.terminal(), wf); err != nil {
+				return err
+			}
+		case count.Done < count.Total && parent.FinishedAt != nil:
+			if err := s.setStatus(ctx, parent, wf.initial(), wf); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+
+		if err := s.repository.Update(ctx, parent); err != nil {
+			return err
+		}
+
+		parentID = parent.ParentID
+	}
+	return nil
+}
+
+func (s *service) toResponse(ctx context.Context, userID uuid.UUID, task Task) (*TaskResponseDTO, error) {
+	responses, err := s.toResponseList(ctx, userID, []Task{task})
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+// toResponseList converts tasks into DTOs, filling in the subtask progress
+// of every task that has children, the time tracked on each task and its
+// blockers.
+func (s *service) toResponseList(ctx context.Context, userID uuid.UUID, tasks []Task) ([]TaskResponseDTO, error) {
+	responses := ToResponseList(tasks)
+
+	ids := make([]uuid.UUID, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.ID
+	}
+
+	counts, err := s.repository.CountSubtasks(ctx, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	tracked, err := s.repository.SumTrackedSeconds(ctx, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	edges, err := s.repository.GetDependencyEdges(ctx, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	blockedBy := make(map[uuid.UUID][]uuid.UUID)
+	blocked := make(map[uuid.UUID]bool)
+	for _, e := range edges {
+		blockedBy[e.TaskID] = append(blockedBy[e.TaskID], e.BlockedByID)
+		if e.Pending {
+			blocked[e.TaskID] = true
+		}
+	}
+
+	for i := range responses {
+		if count, ok := counts[responses[i].ID]; ok && count.Total > 0 {
+			progress := count.Done * 100 / count.Total
+			responses[i].Progress = &progress
+		}
+		responses[i].TrackedSeconds = tracked[responses[i].ID]
+		responses[i].BlockedBy = blockedBy[responses[i].ID]
+		responses[i].IsBlocked = blocked[responses[i].ID]
+	}
+
+	return responses, nil
+}
+
+// normalizeRecurrence validates a raw RRULE and returns its canonical form.
+// An empty rule removes the recurrence.
+func normalizeRecurrence(raw string) (*string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	rule, err := ParseRecurrenceRule(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := rule.String()
+	return &normalized, nil
+}
+
+func occurrenceKey(taskID uuid.UUID, date time.Time) string {
+	return fmt.Sprintf("%s@%d", taskID, date.UnixMicro())
+}
+
+func toOccurrenceResponse(t Task, date time.Time, override *TaskOccurrence) OccurrenceResponseDTO {
+	occurrence := OccurrenceResponseDTO{
+		TaskID:         t.ID,
+		Title:          t.Title,
+		Status:         t.Status,
+		Priority:       t.Priority,
+		CollectionID:   t.CollectionID,
+		OccurrenceDate: date,
+		StartTime:      date,
+		FinishedAt:     t.FinishedAt,
+		IsRecurring:    t.RecurrenceRule != nil,
+	}
+
+	if t.EndTime != nil {
+		end := date.Add(t.EndTime.Sub(t.StartTime))
+		occurrence.EndTime = &end
+	}
+
+	if override != nil {
+		occurrence.Status = override.Status
+		occurrence.FinishedAt = override.FinishedAt
+	}
+
+	return occurrence
+}
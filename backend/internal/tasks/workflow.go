@@ -0,0 +1,109 @@
+package tasks
+
+import (
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos/internal/collections"
+)
+
+const (
+	boardPositionStep = 1024.0
+	minBoardGap       = 1e-6
+)
+
+// workflow is the ordered set of statuses a task can move through. Tasks
+// without a collection, or in a collection without custom columns, use the
+// default PENDING -> DONE workflow.
+type workflow struct {
+	columns []collections.WorkflowColumn
+}
+
+var defaultWorkflow = &workflow{
+	columns: []collections.WorkflowColumn{
+		{Key: string(Pending), Name: "Pendente"},
+		{Key: string(Done), Name: "Concluída", IsTerminal: true},
+	},
+}
+
+func (w *workflow) column(status Status) (*collections.WorkflowColumn, bool) {
+	for i := range w.columns {
+		if w.columns[i].Key == string(status) {
+			return &w.columns[i], true
+		}
+	}
+	return nil, false
+}
+
+func (w *workflow) has(status Status) bool {
+	_, ok := w.column(status)
+	return ok
+}
+
+func (w *workflow) isTerminal(status Status) bool {
+	c, ok := w.column(status)
+	return ok && c.IsTerminal
+}
+
+// initial is the first open column, where new and reopened tasks land.
+func (w *workflow) initial() Status {
+	for _, c := range w.columns {
+		if !c.IsTerminal {
+			return Status(c.Key)
+		}
+	}
+	return Pending
+}
+
+// terminal is the first terminal column, used when a task is completed
+// without naming a specific column.
+func (w *workflow) terminal() Status {
+	for _, c := range w.columns {
+		if c.IsTerminal {
+			return Status(c.Key)
+		}
+	}
+	return Done
+}
+
+// resolve maps a requested status to a column of the workflow. PENDING and
+// DONE are accepted by every workflow as aliases for its initial and
+// terminal columns, so clients unaware of custom columns keep working.
+func (w *workflow) resolve(status Status) (Status, error) {
+	if w.has(status) {
+		return status, nil
+	}
+	switch status {
+	case Pending:
+		return w.initial(), nil
+	case Done:
+		return w.terminal(), nil
+	default:
+		return "", ErrInvalidTaskStatus
+	}
+}
+
+// canTransition reports whether a task may move from one column to another.
+// A column without allowed transitions may move anywhere, and tasks whose
+// status is not part of the workflow (e.g. after the columns were edited)
+// may move to any column.
+func (w *workflow) canTransition(from, to Status) bool {
+	if from == to {
+		return true
+	}
+	c, ok := w.column(from)
+	if !ok || len(c.AllowedTransitions) == 0 {
+		return true
+	}
+	for _, key := range c.AllowedTransitions {
+		if key == string(to) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameCollection(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
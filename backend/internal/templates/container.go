@@ -0,0 +1,26 @@
+package templates
+
+import (
+	"github.com/saulo-duarte/chronos/internal/auth"
+	"github.com/saulo-duarte/chronos/internal/collections"
+	"github.com/saulo-duarte/chronos/internal/tasks"
+	"gorm.io/gorm"
+)
+
+type Container struct {
+	Repository Repository
+	Service    Service
+	Handler    *Handler
+}
+
+func NewContainer(db *gorm.DB, taskService tasks.Service, collectionRepository collections.Repository, locator auth.Locator) *Container {
+	repo := NewRepository(db)
+	svc := NewService(repo, taskService, collectionRepository, locator)
+	hdl := NewHandler(svc)
+
+	return &Container{
+		Repository: repo,
+		Service:    svc,
+		Handler:    hdl,
+	}
+}
@@ -0,0 +1,105 @@
+package templates
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos/internal/tasks"
+)
+
+type TemplateItemDTO struct {
+	TitlePattern       string         `json:"title_pattern" validate:"required"`
+	Description        *string        `json:"description,omitempty"`
+	Priority           tasks.Priority `json:"priority" validate:"required"`
+	StartOffsetMinutes int            `json:"start_offset_minutes"`
+	DurationMinutes    *int           `json:"duration_minutes,omitempty"`
+}
+
+// SaveTemplateDTO is used both to create a template and to replace it.
+type SaveTemplateDTO struct {
+	Name         string            `json:"name" validate:"required,max=100"`
+	Description  *string           `json:"description,omitempty"`
+	CollectionID *uuid.UUID        `json:"collection_id,omitempty"`
+	Items        []TemplateItemDTO `json:"items" validate:"required"`
+}
+
+// InstantiateDTO anchors a template at a date (YYYY-MM-DD, midnight in
+// Timezone, which defaults to the user's time zone) or instant (RFC 3339). Variables fill the {{name}} placeholders
+// of the title patterns besides the built-in {{date}}, {{weekday}} and
+// {{n}}.
+type InstantiateDTO struct {
+	Anchor       string            `json:"anchor" validate:"required"`
+	Timezone     string            `json:"tz,omitempty"`
+	CollectionID *uuid.UUID        `json:"collection_id,omitempty"`
+	Variables    map[string]string `json:"variables,omitempty"`
+}
+
+type TemplateItemResponseDTO struct {
+	ID                 uuid.UUID      `json:"id"`
+	Position           int            `json:"position"`
+	TitlePattern       string         `json:"title_pattern"`
+	Description        *string        `json:"description,omitempty"`
+	Priority           tasks.Priority `json:"priority"`
+	StartOffsetMinutes int            `json:"start_offset_minutes"`
+	DurationMinutes    *int           `json:"duration_minutes,omitempty"`
+}
+
+type TemplateResponseDTO struct {
+	ID           uuid.UUID                 `json:"id"`
+	Name         string                    `json:"name"`
+	Description  *string                   `json:"description,omitempty"`
+	CollectionID *uuid.UUID                `json:"collection_id,omitempty"`
+	Items        []TemplateItemResponseDTO `json:"items"`
+	CreatedAt    time.Time                 `json:"created_at"`
+	UpdatedAt    time.Time                 `json:"updated_at"`
+}
+
+func (dto *SaveTemplateDTO) ToItems(templateID uuid.UUID) []TemplateItem {
+	items := make([]TemplateItem, len(dto.Items))
+	for i, item := range dto.Items {
+		items[i] = TemplateItem{
+			ID:                 uuid.New(),
+			TemplateID:         templateID,
+			Position:           i,
+			TitlePattern:       item.TitlePattern,
+			Description:        item.Description,
+			Priority:           item.Priority,
+			StartOffsetMinutes: item.StartOffsetMinutes,
+			DurationMinutes:    item.DurationMinutes,
+		}
+	}
+	return items
+}
+
+func ToResponse(t Template) TemplateResponseDTO {
+	items := make([]TemplateItemResponseDTO, len(t.Items))
+	for i, item := range t.Items {
+		items[i] = TemplateItemResponseDTO{
+			ID:                 item.ID,
+			Position:           item.Position,
+			TitlePattern:       item.TitlePattern,
+			Description:        item.Description,
+			Priority:           item.Priority,
+			StartOffsetMinutes: item.StartOffsetMinutes,
+			DurationMinutes:    item.DurationMinutes,
+		}
+	}
+
+	return TemplateResponseDTO{
+		ID:           t.ID,
+		Name:         t.Name,
+		Description:  t.Description,
+		CollectionID: t.CollectionID,
+		Items:        items,
+		CreatedAt:    t.CreatedAt,
+		UpdatedAt:    t.UpdatedAt,
+	}
+}
+
+func ToResponseList(templates []Template) []TemplateResponseDTO {
+	responses := make([]TemplateResponseDTO, len(templates))
+	for i, t := range templates {
+		responses[i] = ToResponse(t)
+	}
+	return responses
+}
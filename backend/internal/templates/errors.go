@@ -0,0 +1,13 @@
+package templates
+
+import "errors"
+
+var (
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrTemplateNotFound   = errors.New("template not found")
+	ErrInvalidTemplate    = errors.New("template needs a name and 1 to 100 items with title, valid priority and non-negative duration")
+	ErrCollectionNotFound = errors.New("collection not found")
+	ErrInvalidAnchor      = errors.New("invalid anchor date")
+	ErrInvalidTimezone    = errors.New("invalid time zone")
+	ErrMissingVariable    = errors.New("title pattern uses a variable that was not provided")
+)
@@ -0,0 +1,143 @@
+package templates
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos/internal/shared/response"
+	"github.com/saulo-duarte/chronos/internal/tasks"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var dto SaveTemplateDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_PAYLOAD", "Payload inválido")
+		return
+	}
+
+	template, err := h.service.Create(r.Context(), &dto)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, template)
+}
+
+func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.service.GetAll(r.Context())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, templates)
+}
+
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	template, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, template)
+}
+
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	var dto SaveTemplateDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_PAYLOAD", "Payload inválido")
+		return
+	}
+
+	template, err := h.service.Update(r.Context(), id, &dto)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, template)
+}
+
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Template excluído com sucesso"})
+}
+
+func (h *Handler) Instantiate(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	var dto InstantiateDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "INVALID_PAYLOAD", "Payload inválido")
+		return
+	}
+
+	created, err := h.service.Instantiate(r.Context(), id, &dto)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, created)
+}
+
+func (h *Handler) handleError(w http.ResponseWriter, err error) {
+	switch err {
+	case ErrTemplateNotFound:
+		response.Error(w, http.StatusNotFound, "TEMPLATE_NOT_FOUND", err.Error())
+	case ErrCollectionNotFound, tasks.ErrCollectionNotFound:
+		response.Error(w, http.StatusNotFound, "COLLECTION_NOT_FOUND", err.Error())
+	case ErrUnauthorized, tasks.ErrUnauthorized:
+		response.Error(w, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+	case ErrInvalidTemplate:
+		response.Error(w, http.StatusBadRequest, "INVALID_TEMPLATE", err.Error())
+	case ErrInvalidAnchor:
+		response.Error(w, http.StatusBadRequest, "INVALID_ANCHOR", err.Error())
+	case ErrInvalidTimezone:
+		response.Error(w, http.StatusBadRequest, "INVALID_TIMEZONE", err.Error())
+	case ErrMissingVariable:
+		response.Error(w, http.StatusBadRequest, "MISSING_VARIABLE", err.Error())
+	case tasks.ErrInvalidTaskPriority, tasks.ErrInvalidDateRange:
+		response.Error(w, http.StatusBadRequest, "INVALID_TEMPLATE", err.Error())
+	default:
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Erro interno no servidor")
+	}
+}
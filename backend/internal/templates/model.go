@@ -0,0 +1,58 @@
+package templates
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos/internal/tasks"
+	"gorm.io/gorm"
+)
+
+// Template is a reusable set of tasks, e.g. the study plan of a course.
+type Template struct {
+	ID           uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey"`
+	UserID       uuid.UUID      `json:"user_id" gorm:"type:uuid;index;not null"`
+	Name         string         `json:"name" gorm:"not null"`
+	Description  *string        `json:"description,omitempty"`
+	CollectionID *uuid.UUID     `json:"collection_id,omitempty" gorm:"type:uuid"`
+	Items        []TemplateItem `json:"items" gorm:"foreignKey:TemplateID"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
+
+func (Template) TableName() string {
+	return "task_templates"
+}
+
+func (t *Template) BeforeCreate(tx *gorm.DB) (err error) {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return
+}
+
+// TemplateItem describes one task of a template. Its start is an offset
+// from the anchor chosen when the template is instantiated.
+type TemplateItem struct {
+	ID                 uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey"`
+	TemplateID         uuid.UUID      `json:"template_id" gorm:"type:uuid;index;not null"`
+	Position           int            `json:"position" gorm:"not null"`
+	TitlePattern       string         `json:"title_pattern" gorm:"not null"`
+	Description        *string        `json:"description,omitempty"`
+	Priority           tasks.Priority `json:"priority" gorm:"not null"`
+	StartOffsetMinutes int            `json:"start_offset_minutes" gorm:"not null"`
+	DurationMinutes    *int           `json:"duration_minutes,omitempty"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+}
+
+func (TemplateItem) TableName() string {
+	return "task_template_items"
+}
+
+func (i *TemplateItem) BeforeCreate(tx *gorm.DB) (err error) {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,89 @@
+package templates
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Create(ctx context.Context, template *Template) error
+	GetByID(ctx context.Context, id, userID uuid.UUID) (*Template, error)
+	GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]Template, error)
+	Replace(ctx context.Context, template *Template) error
+	Delete(ctx context.Context, id, userID uuid.UUID) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func orderedItems(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC")
+}
+
+func (r *repository) Create(ctx context.Context, template *Template) error {
+	return r.db.WithContext(ctx).Create(template).Error
+}
+
+func (r *repository) GetByID(ctx context.Context, id, userID uuid.UUID) (*Template, error) {
+	var template Template
+
+	err := r.db.WithContext(ctx).
+		Preload("Items", orderedItems).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&template).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+func (r *repository) GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]Template, error) {
+	var templates []Template
+
+	err := r.db.WithContext(ctx).
+		Preload("Items", orderedItems).
+		Where("user_id = ?", userID).
+		Order("name ASC").
+		Find(&templates).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// Replace saves the template fields and swaps its items for template.Items.
+func (r *repository) Replace(ctx context.Context, template *Template) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("template_id = ?", template.ID).Delete(&TemplateItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Omit("Items").Save(template).Error; err != nil {
+			return err
+		}
+		if len(template.Items) == 0 {
+			return nil
+		}
+		return tx.Create(&template.Items).Error
+	})
+}
+
+func (r *repository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", id, userID).Delete(&Template{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return tx.Where("template_id = ?", id).Delete(&TemplateItem{}).Error
+	})
+}
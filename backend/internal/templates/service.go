@@ -0,0 +1,272 @@
+package templates
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos/internal/auth"
+	"github.com/saulo-duarte/chronos/internal/collections"
+	"github.com/saulo-duarte/chronos/internal/shared/middlewares"
+	"github.com/saulo-duarte/chronos/internal/tasks"
+)
+
+type Service interface {
+	Create(ctx context.Context, dto *SaveTemplateDTO) (*TemplateResponseDTO, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*TemplateResponseDTO, error)
+	GetAll(ctx context.Context) ([]TemplateResponseDTO, error)
+	Update(ctx context.Context, id uuid.UUID, dto *SaveTemplateDTO) (*TemplateResponseDTO, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	Instantiate(ctx context.Context, id uuid.UUID, dto *InstantiateDTO) ([]tasks.TaskResponseDTO, error)
+}
+
+const maxTemplateItems = 100
+
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+type service struct {
+	repository  Repository
+	tasks       tasks.Service
+	collections collections.Repository
+	locations   auth.Locator
+}
+
+func NewService(repository Repository, taskService tasks.Service, collectionRepository collections.Repository, locator auth.Locator) Service {
+	return &service{
+		repository:  repository,
+		tasks:       taskService,
+		collections: collectionRepository,
+		locations:   locator,
+	}
+}
+
+func (s *service) Create(ctx context.Context, dto *SaveTemplateDTO) (*TemplateResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	if err := s.validate(ctx, userID, dto); err != nil {
+		return nil, err
+	}
+
+	template := &Template{
+		ID:           uuid.New(),
+		UserID:       userID,
+		Name:         strings.TrimSpace(dto.Name),
+		Description:  dto.Description,
+		CollectionID: dto.CollectionID,
+	}
+	template.Items = dto.ToItems(template.ID)
+
+	if err := s.repository.Create(ctx, template); err != nil {
+		return nil, err
+	}
+
+	res := ToResponse(*template)
+	return &res, nil
+}
+
+func (s *service) GetByID(ctx context.Context, id uuid.UUID) (*TemplateResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	template, err := s.repository.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, ErrTemplateNotFound
+	}
+
+	res := ToResponse(*template)
+	return &res, nil
+}
+
+func (s *service) GetAll(ctx context.Context) ([]TemplateResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	templates, err := s.repository.GetAllByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToResponseList(templates), nil
+}
+
+func (s *service) Update(ctx context.Context, id uuid.UUID, dto *SaveTemplateDTO) (*TemplateResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	template, err := s.repository.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, ErrTemplateNotFound
+	}
+
+	if err := s.validate(ctx, userID, dto); err != nil {
+		return nil, err
+	}
+
+	template.Name = strings.TrimSpace(dto.Name)
+	template.Description = dto.Description
+	template.CollectionID = dto.CollectionID
+	template.Items = dto.ToItems(template.ID)
+
+	if err := s.repository.Replace(ctx, template); err != nil {
+		return nil, err
+	}
+
+	res := ToResponse(*template)
+	return &res, nil
+}
+
+func (s *service) Delete(ctx context.Context, id uuid.UUID) error {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return ErrUnauthorized
+	}
+
+	if _, err := s.repository.GetByID(ctx, id, userID); err != nil {
+		return ErrTemplateNotFound
+	}
+
+	return s.repository.Delete(ctx, id, userID)
+}
+
+// Instantiate creates one task per template item, starting at the anchor
+// plus the item offset. Offsets are applied on the wall clock of the
+// requested time zone, or the user's when none is given, so "day 2 at 09:00" stays at 09:00 across DST
+// changes. All tasks are created in a single transaction.
+func (s *service) Instantiate(ctx context.Context, id uuid.UUID, dto *InstantiateDTO) ([]tasks.TaskResponseDTO, error) {
+	userID, err := middlewares.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	template, err := s.repository.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, ErrTemplateNotFound
+	}
+
+	var loc *time.Location
+	if dto.Timezone != "" {
+		if loc, err = time.LoadLocation(dto.Timezone); err != nil {
+			return nil, ErrInvalidTimezone
+		}
+	} else if loc, err = s.locations.Location(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	anchor, err := parseAnchor(dto.Anchor, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	collectionID := template.CollectionID
+	if dto.CollectionID != nil {
+		collectionID = dto.CollectionID
+	}
+	if collectionID != nil {
+		if _, err := s.collections.GetByID(ctx, *collectionID, userID); err != nil {
+			return nil, ErrCollectionNotFound
+		}
+	}
+
+	dtos := make([]tasks.CreateTaskDTO, len(template.Items))
+	for i, item := range template.Items {
+		start := time.Date(anchor.Year(), anchor.Month(), anchor.Day(),
+			anchor.Hour(), anchor.Minute()+item.StartOffsetMinutes, 0, 0, loc)
+
+		title, err := render(item.TitlePattern, dto.Variables, start, i+1)
+		if err != nil {
+			return nil, err
+		}
+
+		dtos[i] = tasks.CreateTaskDTO{
+			Title:        title,
+			Description:  item.Description,
+			Status:       tasks.Pending,
+			Priority:     item.Priority,
+			CollectionID: collectionID,
+			StartTime:    start,
+		}
+		if item.DurationMinutes != nil {
+			end := start.Add(time.Duration(*item.DurationMinutes) * time.Minute)
+			dtos[i].EndTime = &end
+		}
+	}
+
+	return s.tasks.CreateMany(ctx, dtos)
+}
+
+func (s *service) validate(ctx context.Context, userID uuid.UUID, dto *SaveTemplateDTO) error {
+	if strings.TrimSpace(dto.Name) == "" || len(dto.Items) == 0 || len(dto.Items) > maxTemplateItems {
+		return ErrInvalidTemplate
+	}
+
+	for _, item := range dto.Items {
+		if strings.TrimSpace(item.TitlePattern) == "" || !item.Priority.IsValid() {
+			return ErrInvalidTemplate
+		}
+		if item.DurationMinutes != nil && *item.DurationMinutes < 0 {
+			return ErrInvalidTemplate
+		}
+	}
+
+	if dto.CollectionID != nil {
+		if _, err := s.collections.GetByID(ctx, *dto.CollectionID, userID); err != nil {
+			return ErrCollectionNotFound
+		}
+	}
+
+	return nil
+}
+
+// parseAnchor accepts a date, taken as midnight in loc, or an RFC 3339
+// instant, converted to loc.
+func parseAnchor(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.In(loc), nil
+	}
+	return time.Time{}, ErrInvalidAnchor
+}
+
+// render replaces the {{name}} placeholders of pattern. Besides the caller
+// variables, {{date}}, {{weekday}} and {{n}} (1-based item number) are
+// always available; caller variables take precedence.
+func render(pattern string, variables map[string]string, start time.Time, n int) (string, error) {
+	var missing bool
+
+	title := placeholderPattern.ReplaceAllStringFunc(pattern, func(match string) string {
+		name := placeholderPattern.FindStringSubmatch(match)[1]
+		if value, ok := variables[name]; ok {
+			return value
+		}
+		switch name {
+		case "date":
+			return start.Format("2006-01-02")
+		case "weekday":
+			return start.Weekday().String()
+		case "n":
+			return strconv.Itoa(n)
+		}
+		missing = true
+		return match
+	})
+
+	if missing {
+		return "", ErrMissingVariable
+	}
+
+	return strings.TrimSpace(title), nil
+}
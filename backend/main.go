@@ -2,20 +2,24 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"log"
 	"net/http"
 	"os"
+	_ "time/tzdata"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	chiadapter "github.com/awslabs/aws-lambda-go-api-proxy/chi"
 	"github.com/go-chi/chi/v5"
 	"github.com/saulo-duarte/chronos/internal/container"
+	"github.com/saulo-duarte/chronos/internal/shared/jobs"
 	"github.com/saulo-duarte/chronos/internal/shared/router"
 )
 
 var chiLambda *chiadapter.ChiLambdaV2
 var chiRouter *chi.Mux
+var jobRunner *jobs.Runner
 
 func init() {
 	c := container.New()
@@ -28,14 +32,37 @@ func init() {
 		ResourceHandler:   c.ResourceHandler,
 		LeetCodeHandler:   c.LeetCodeHandler,
 		ObjectiveHandler:  c.ObjectiveHandler,
+		CalendarHandler:   c.CalendarHandler,
+		PomodoroHandler:   c.PomodoroHandler,
+		TemplateHandler:   c.TemplateHandler,
 		JWTService:        c.JWTService,
 	})
 
 	chiRouter = r
 	chiLambda = chiadapter.NewV2(chiRouter)
+	jobRunner = c.Jobs
 }
 
-func Handler(ctx context.Context, req events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+// scheduledJob is the detail of an EventBridge scheduled event. An empty
+// job name runs every job.
+type scheduledJob struct {
+	Job string `json:"job"`
+}
+
+// Handler serves both API Gateway requests and EventBridge scheduled
+// events, which trigger the background jobs.
+func Handler(ctx context.Context, payload json.RawMessage) (any, error) {
+	var event events.CloudWatchEvent
+	if err := json.Unmarshal(payload, &event); err == nil && event.Source == "aws.events" {
+		var detail scheduledJob
+		_ = json.Unmarshal(event.Detail, &detail)
+		return nil, jobRunner.Run(ctx, detail.Job)
+	}
+
+	var req events.APIGatewayV2HTTPRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return nil, err
+	}
 	return chiLambda.ProxyWithContextV2(ctx, req)
 }
 
@@ -45,6 +72,7 @@ func main() {
 		if port == "" {
 			port = "3001"
 		}
+		jobRunner.Start(context.Background())
 		log.Printf("🚀 Servidor local iniciado em http://localhost:%s\n", port)
 		log.Fatal(http.ListenAndServe(":"+port, chiRouter))
 	} else {
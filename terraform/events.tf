@@ -0,0 +1,53 @@
+# Scheduled events that run the background jobs on Lambda. The constant input
+# replaces the event sent to the function, so it keeps the "aws.events"
+# source the handler looks for and names the job in detail.job.
+
+resource "aws_cloudwatch_event_rule" "task_rollover" {
+  name                = "${var.lambda_function_name}-task-rollover"
+  description         = "Aplica as políticas de rollover das tarefas atrasadas"
+  schedule_expression = "rate(1 hour)"
+}
+
+resource "aws_cloudwatch_event_target" "task_rollover" {
+  rule = aws_cloudwatch_event_rule.task_rollover.name
+  arn  = aws_lambda_function.go_lambda.arn
+
+  input = jsonencode({
+    source        = "aws.events"
+    "detail-type" = "Scheduled Event"
+    detail        = { job = "task-rollover" }
+  })
+}
+
+resource "aws_lambda_permission" "task_rollover" {
+  statement_id  = "AllowExecutionFromEventBridgeTaskRollover"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.go_lambda.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.task_rollover.arn
+}
+
+resource "aws_cloudwatch_event_rule" "task_trash_purge" {
+  name                = "${var.lambda_function_name}-task-trash-purge"
+  description         = "Remove definitivamente as tarefas antigas da lixeira"
+  schedule_expression = "rate(1 day)"
+}
+
+resource "aws_cloudwatch_event_target" "task_trash_purge" {
+  rule = aws_cloudwatch_event_rule.task_trash_purge.name
+  arn  = aws_lambda_function.go_lambda.arn
+
+  input = jsonencode({
+    source        = "aws.events"
+    "detail-type" = "Scheduled Event"
+    detail        = { job = "task-trash-purge" }
+  })
+}
+
+resource "aws_lambda_permission" "task_trash_purge" {
+  statement_id  = "AllowExecutionFromEventBridgeTaskTrashPurge"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.go_lambda.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.task_trash_purge.arn
+}